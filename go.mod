@@ -2,11 +2,16 @@ module github.com/vps2/cisco-switches-crawler
 
 go 1.19
 
-require github.com/reiver/go-telnet v0.0.0-20180421082511-9ff0b2ab096e
+require (
+	github.com/gosnmp/gosnmp v1.35.0
+	github.com/reiver/go-telnet v0.0.0-20180421082511-9ff0b2ab096e
+	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.17.0
+)
 
-require golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
+require golang.org/x/sys v0.13.0 // indirect
 
 require (
 	github.com/reiver/go-oi v1.0.0 // indirect
-	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
+	golang.org/x/term v0.13.0
 )
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func runAnalyze(args []string) {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	input := flags.String("input", "", "the file with the result of the crawl. If not specified, the result is read from stdin")
+	flags.Parse(args)
+
+	network, err := loadNetwork(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Print(formatSummary(network))
+	fmt.Print(formatRedundancy(network))
+	fmt.Print(formatVLANMismatches(network))
+	fmt.Print(formatSpanningTree(network))
+}
+
+// formatRedundancy prints the switches and the links whose failure would partition the network
+func formatRedundancy(network *domain.Network) string {
+	var sb strings.Builder
+
+	sb.WriteString("Switches that are single points of failure:\n")
+	cutSwitches := network.ArticulationPoints()
+	if len(cutSwitches) == 0 {
+		sb.WriteString("    none\n")
+	}
+	for _, cut := range cutSwitches {
+		parts := make([]string, 0, len(cut.Parts))
+		for _, part := range cut.Parts {
+			parts = append(parts, fmt.Sprint(part))
+		}
+		sb.WriteString(fmt.Sprintf("    %s (%s): the network splits into parts of %s switches\n",
+			cut.Switch.Name(), cut.Switch.Address(), strings.Join(parts, ", ")))
+	}
+
+	sb.WriteString("Links that are single points of failure:\n")
+	cutLinks := network.Bridges()
+	if len(cutLinks) == 0 {
+		sb.WriteString("    none\n")
+	}
+	for _, cut := range cutLinks {
+		from, _ := network.Switch(cut.Link.FromAddress())
+		to, _ := network.Switch(cut.Link.ToAddress())
+		sb.WriteString(fmt.Sprintf("    %s (%s) %s <-> %s %s (%s): the network splits into parts of %d and %d switches\n",
+			from.Name(), from.Address(), portOrUnknown(cut.Link.FromPort()),
+			portOrUnknown(cut.Link.ToPort()), to.Name(), to.Address(),
+			cut.FromPart, cut.ToPart))
+	}
+
+	return sb.String()
+}
@@ -8,10 +8,16 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/backup"
 	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/cisco"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/profile"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/snmp"
 	"github.com/vps2/cisco-switches-crawler/internal/usecase"
 	"github.com/vps2/cisco-switches-crawler/pkg/ip"
 	"github.com/vps2/cisco-switches-crawler/pkg/telnet"
@@ -19,49 +25,127 @@ import (
 )
 
 var (
-	rootDevIP string
-	verbose   bool
-	include   string
-	pretty    bool
+	rootDevIP    string
+	verbose      bool
+	include      string
+	family       string
+	hosts        bool
+	vlans        bool
+	stp          bool
+	output       exportOptions
+	execution    execOptions
+	snmpFlags    snmpOptions
+	driverFlags  driverOptions
+	jumpFlags    jumpOptions
+	profileFlags profileOptions
+	retryFlags   retryOptions
+	checkpoints  checkpointOptions
+	dnsFlags     dnsOptions
 )
 
+// hostNameRe - the host name of the seed switch, e.g. core1.corp.local
+var hostNameRe = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9\-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9\-]*[A-Za-z0-9])?)*\.?$`)
+
 var (
 	user     string
 	password string
 )
 
+var (
+	backupDir string
+	backupGit bool
+)
+
+// commands - subcommands of the application. Without a subcommand the application crawls the network.
+var commands = map[string]func(args []string){
+	"path":    runPath,
+	"analyze": runAnalyze,
+	"export":  runExport,
+	"exec":    runExec,
+	"locate":  runLocate,
+	"vlan":    runVLAN,
+}
+
 func Run() {
-	flag.StringVar(&rootDevIP, "address", "", "ip address of the switch")
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			command(os.Args[2:])
+			return
+		}
+	}
+
+	crawl()
+}
+
+func crawl() {
+	flag.StringVar(&rootDevIP, "address", "", "ip address or host name of the switch")
 	flag.StringVar(&user, "user", "", "the name of the user to access the switches")
 	flag.StringVar(&password, "password", "", "the user's password. If not specified, the application will ask for a password")
 	flag.BoolVar(&verbose, "verbose", false, "show verbose")
-	flag.StringVar(&include, "include", "", "ip addresses (separated by commas) included in the selection. Example: [192.168.1.1,192.168.1.0/24]")
-	flag.BoolVar(&pretty, "pretty", false, "beautiful print of the result")
+	flag.StringVar(&include, "include", "", "ip addresses (separated by commas) included in the selection. Example: [192.168.1.1,192.168.1.0/24,2001:db8::/48]")
+	flag.StringVar(&family, "address-family", string(domain.FamilyIPv4), "the preferred family of the addresses of the neighbors reporting both IPv4 and IPv6 addresses: ipv4 or ipv6")
+	flag.StringVar(&backupDir, "backup", "", "the directory to save the running configuration of every crawled switch as <hostname>.cfg")
+	flag.BoolVar(&backupGit, "backup-git", false, "commit the changes of the -backup directory to the local git repository")
+	flag.BoolVar(&vlans, "vlans", false, "collect the trunks and the VLAN databases of the switches (see the vlan subcommand)")
+	flag.BoolVar(&stp, "stp", false, "collect the state of the spanning tree of the switches (see the analyze subcommand)")
+	flag.BoolVar(&hosts, "hosts", false, "collect the MAC address tables and the ARP tables of the switches to locate the end hosts (see the locate subcommand)")
+	output.register(flag.CommandLine)
+	execution.register(flag.CommandLine)
+	snmpFlags.register(flag.CommandLine)
+	driverFlags.register(flag.CommandLine)
+	jumpFlags.register(flag.CommandLine)
+	profileFlags.register(flag.CommandLine)
+	retryFlags.register(flag.CommandLine)
+	checkpoints.register(flag.CommandLine)
+	dnsFlags.register(flag.CommandLine)
 	flag.Parse()
 
-	if rootDevIP == "" {
+	if rootDevIP == "" && !checkpoints.resume {
 		log.Fatal("IP address of the switch is empty")
 	}
-	if ok := checkIP(rootDevIP); !ok {
-		log.Fatal("IP address of the switch is incorrect")
+	if rootDevIP != "" && !checkHost(rootDevIP) { //without the address the crawl is resumed from the checkpoint
+		log.Fatal("IP address or host name of the switch is incorrect")
+	}
+	addressFamily, err := domain.ParseAddressFamily(family)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	if user == "" {
-		log.Fatal("The user name for accessing the switches is not set")
+	if _, err := output.renderer(); err != nil {
+		log.Fatal(err)
 	}
-	if password == "" {
-		fmt.Print("password: ")
-		var err error
-		if password, err = readUserPassword(); err != nil {
-			log.Fatal("Error receiving the user's password")
-		}
 
-		fmt.Println()
+	commands, commandStore, err := execution.store()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		if password == "" {
-			log.Fatal("An empty password is not allowed")
+	useSNMP, err := snmpFlags.enabled()
+	if err != nil {
+		log.Fatal(err)
+	}
+	snmpFilter, err := snmpFlags.filter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	var snmpClient *snmp.Client
+	if useSNMP || snmpFilter != nil {
+		if snmpClient, err = snmpFlags.client(); err != nil {
+			log.Fatal(err)
 		}
 	}
+	if !useSNMP {
+		checkCredentials()
+	}
+	clientOpts, err := driverFlags.clientOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	telnetOpts, err := jumpFlags.telnetOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer jumpFlags.close()
 
 	ipFilter := ip.NewFilter(ip.AllowAnyIfEmpty(true))
 	if include != "" {
@@ -75,23 +159,125 @@ func Run() {
 
 	//--------------------------------------------------------------------------------------------------------------------
 
-	telnet := telnet.New()
-	var networkBuilder *usecase.NetworkBuilder
+	retryPolicy, err := retryFlags.policy()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	builderOpts := []usecase.Option{usecase.WithIPFiltering(ipFilter), usecase.WithAddressFamily(addressFamily), usecase.WithRetry(retryPolicy)}
+
+	checkpointOpts, err := checkpoints.builderOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	builderOpts = append(builderOpts, checkpointOpts...)
 
+	dnsOpts, err := dnsFlags.builderOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	builderOpts = append(builderOpts, dnsOpts...)
+
+	var configBackup *backup.Dir
+	if backupDir != "" {
+		var err error
+		if configBackup, err = backup.NewDir(backupDir); err != nil {
+			log.Fatal(err)
+		}
+		builderOpts = append(builderOpts, usecase.WithConfigBackup(configBackup))
+	} else if backupGit {
+		log.Fatal("The -backup-git flag requires the -backup directory")
+	}
+
+	if vlans {
+		builderOpts = append(builderOpts, usecase.WithVLANs())
+	}
+	if stp {
+		builderOpts = append(builderOpts, usecase.WithSpanningTree())
+	}
+	if hosts {
+		builderOpts = append(builderOpts, usecase.WithHostTables())
+	}
+
+	if commandStore != nil {
+		builderOpts = append(builderOpts, usecase.WithCommands(commands, commandStore))
+	}
+
+	var client usecase.Client = snmpClient
+	if !useSNMP {
+		client = cisco.NewClient(telnet.New(telnetOpts...), clientOpts...)
+		if snmpFilter != nil {
+			builderOpts = append(builderOpts, usecase.WithBackend(snmpFilter, snmpClient))
+		}
+	}
+	profiles, err := profileFlags.resolver(func(address string) string {
+		if useSNMP || (snmpFilter != nil && snmpFilter.Allow(net.ParseIP(address))) {
+			return profile.TransportSNMP
+		}
+		return profile.TransportTelnet
+	}, func(p profile.Profile, transport string) (usecase.Client, error) {
+		if transport == profile.TransportSNMP {
+			return profileSNMPClient(p)
+		}
+		return profileTelnetClient(p, telnetOpts, clientOpts), nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if profiles != nil {
+		builderOpts = append(builderOpts, usecase.WithProfiles(profiles))
+	}
 	if verbose {
-		client := cisco.NewClient(telnet, cisco.WithVerbose())
-		networkBuilder = usecase.NewNetworkBuilder(client, usecase.WithShowOutput(), usecase.WithIPFiltering(ipFilter))
-	} else {
-		client := cisco.NewClient(telnet)
-		networkBuilder = usecase.NewNetworkBuilder(client, usecase.WithIPFiltering(ipFilter))
+		builderOpts = append(builderOpts, usecase.WithShowOutput())
 	}
+	networkBuilder := usecase.NewNetworkBuilder(client, builderOpts...)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := interruptibleContext()
 	defer cancel()
 
+	networkBuilder.Build(ctx, rootDevIP, user, password) //the host name is resolved by the network builder
+	fmt.Println()
+	if backupGit {
+		if err := configBackup.Commit("Backup of the configurations " + time.Now().Format(time.RFC3339)); err != nil {
+			log.Println(err)
+		}
+	}
+	if err := execution.finish(); err != nil {
+		log.Println(err)
+	}
+	fmt.Fprint(os.Stderr, formatSummary(networkBuilder.Network()))
+	if err := output.write(os.Stdout, networkBuilder.Network()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// checkCredentials checks the name of the user and asks for the password if it is not set
+func checkCredentials() {
+	if user == "" {
+		log.Fatal("The user name for accessing the switches is not set")
+	}
+	if password == "" {
+		fmt.Print("password: ")
+		var err error
+		if password, err = readUserPassword(); err != nil {
+			log.Fatal("Error receiving the user's password")
+		}
+
+		fmt.Println()
+
+		if password == "" {
+			log.Fatal("An empty password is not allowed")
+		}
+	}
+}
+
+// interruptibleContext returns the context canceled by Ctrl-C or by the termination signal
+func interruptibleContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	go func() {
 		done := make(chan os.Signal, 1)
-		signal.Notify(done, os.Interrupt)
+		signal.Notify(done, os.Interrupt, syscall.SIGTERM)
 
 		select {
 		case <-done:
@@ -101,21 +287,16 @@ func Run() {
 		}
 	}()
 
-	networkBuilder.Build(ctx, rootDevIP, user, password)
-	fmt.Println()
-	if pretty {
-		fmt.Println(string(networkBuilder.ToPrettyJSON()))
-	} else {
-		fmt.Println(string(networkBuilder.ToJSON()))
-	}
+	return ctx, cancel
 }
 
-func checkIP(ip string) bool {
-	if ip := net.ParseIP(ip); ip != nil {
+// checkHost checks that the seed is an ip address or looks like a host name
+func checkHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
 		return true
 	}
 
-	return false
+	return hostNameRe.MatchString(host)
 }
 
 func readUserPassword() (string, error) {
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/checkpoint"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+// checkpointOptions - flags of the checkpoints of the crawl
+type checkpointOptions struct {
+	file     string
+	interval time.Duration
+	resume   bool
+}
+
+func (o *checkpointOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.file, "checkpoint", "", "the file to save the state of the crawl periodically and on Ctrl-C, so that the crawl can be resumed")
+	flags.DurationVar(&o.interval, "checkpoint-interval", time.Minute, "the minimum interval between the saves of the -checkpoint file")
+	flags.BoolVar(&o.resume, "resume", false, "continue the crawl from the -checkpoint file skipping the already crawled switches. "+
+		"The failed switches are polled again")
+}
+
+// builderOptions returns the options of the network builder saving the checkpoints and resuming the crawl
+func (o *checkpointOptions) builderOptions() ([]usecase.Option, error) {
+	if o.file == "" {
+		if o.resume {
+			return nil, errors.New("the -resume flag requires the -checkpoint file")
+		}
+		return nil, nil
+	}
+	if o.interval < 0 {
+		return nil, errors.New("the checkpoint interval must not be negative")
+	}
+
+	file := checkpoint.NewFile(o.file)
+	opts := []usecase.Option{usecase.WithCheckpoint(file, o.interval)}
+	if o.resume {
+		state, err := file.Load()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, usecase.WithResume(state))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+const dnsPort = "53"
+
+// dnsOptions - flags of the name resolution of the seed and the switches
+type dnsOptions struct {
+	reverse bool
+	server  string
+}
+
+func (o *dnsOptions) register(flags *flag.FlagSet) {
+	flags.BoolVar(&o.reverse, "reverse-dns", false, "look up the DNS names of the crawled switches by their addresses (PTR records)")
+	flags.StringVar(&o.server, "dns-server", "", "the DNS server (host[:port]) to resolve the -address and to look up the names of the switches instead of the system resolver")
+}
+
+// builderOptions returns the options of the network builder resolving the names
+func (o *dnsOptions) builderOptions() ([]usecase.Option, error) {
+	var opts []usecase.Option
+	if o.reverse {
+		opts = append(opts, usecase.WithReverseDNS())
+	}
+	if o.server == "" {
+		return opts, nil
+	}
+
+	server := o.server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, dnsPort)
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		return nil, fmt.Errorf("dns server [%s]: %w", o.server, err)
+	}
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+
+	return append(opts, usecase.WithResolver(resolver)), nil
+}
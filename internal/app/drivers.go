@@ -0,0 +1,43 @@
+package app
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/cisco"
+)
+
+// driverOptions - flags of the selection of the drivers of the telnet client
+type driverOptions struct {
+	rules string
+}
+
+func (o *driverOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.rules, "driver-rules", "", "rules (separated by semicolons) selecting the driver of the switches by the name or the platform instead of the recognition: "+
+		"ios, ios-xe, nx-os, catos, aruba, huawei or eltex. Example: \"^MES=eltex;^S57=huawei\"")
+}
+
+// clientOptions returns the options of the telnet client
+func (o *driverOptions) clientOptions() ([]cisco.Option, error) {
+	var rules []cisco.DriverRule
+	for _, value := range strings.Split(o.rules, ";") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		rule, err := cisco.ParseDriverRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	var opts []cisco.Option
+	if len(rules) > 0 {
+		opts = append(opts, cisco.WithDriverRules(rules...))
+	}
+	if verbose {
+		opts = append(opts, cisco.WithVerbose())
+	}
+
+	return opts, nil
+}
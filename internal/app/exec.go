@@ -0,0 +1,159 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/capture"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/cisco"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/profile"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+	"github.com/vps2/cisco-switches-crawler/pkg/telnet"
+)
+
+// execOptions - flags of the execution of the commands shared by the crawl and the exec subcommand
+type execOptions struct {
+	commands string
+	dir      string
+	json     string
+	unsafe   bool
+
+	jsonStore *capture.JSON
+}
+
+func (o *execOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.commands, "exec", "", "commands (separated by semicolons) executed on every switch. Example: \"show vlan brief;show inventory\"")
+	flags.StringVar(&o.dir, "exec-dir", "", "the directory to save the output of the commands as <hostname>/<command>.txt")
+	flags.StringVar(&o.json, "exec-json", "", "the file to save the output of the commands as a JSON document")
+	flags.BoolVar(&o.unsafe, "exec-unsafe", false, "allow the commands that are not in the read-only allowlist")
+}
+
+// store returns the commands and the storage of their output. The storage is nil if the commands are not set.
+func (o *execOptions) store() ([]string, usecase.CommandStore, error) {
+	var commands []string
+	for _, command := range strings.Split(o.commands, ";") {
+		if command = strings.TrimSpace(command); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	if len(commands) == 0 {
+		return nil, nil, nil
+	}
+
+	if !o.unsafe {
+		for _, command := range commands {
+			if err := usecase.CheckReadOnly(command); err != nil {
+				return nil, nil, fmt.Errorf("%w. Use -exec-unsafe to execute it anyway", err)
+			}
+		}
+	}
+
+	switch {
+	case o.dir != "" && o.json != "":
+		return nil, nil, errors.New("only one of -exec-dir and -exec-json can be set")
+	case o.dir != "":
+		dir, err := capture.NewDir(o.dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return commands, dir, nil
+	case o.json != "":
+		o.jsonStore = capture.NewJSON()
+		return commands, o.jsonStore, nil
+	}
+
+	return nil, nil, errors.New("the output of the commands requires -exec-dir or -exec-json")
+}
+
+// finish writes the JSON document with the output of the commands
+func (o *execOptions) finish() error {
+	if o.jsonStore == nil {
+		return nil
+	}
+
+	file, err := os.Create(o.json)
+	if err != nil {
+		return fmt.Errorf("exec output: %w", err)
+	}
+	defer file.Close()
+
+	_, err = o.jsonStore.WriteTo(file)
+
+	return err
+}
+
+func runExec(args []string) {
+	var options execOptions
+
+	flags := flag.NewFlagSet("exec", flag.ExitOnError)
+	input := flags.String("input", "", "the file with the result of the crawl. If not specified, the result is read from stdin")
+	flags.StringVar(&user, "user", "", "the name of the user to access the switches")
+	flags.StringVar(&password, "password", "", "the user's password. If not specified, the application will ask for a password")
+	flags.BoolVar(&verbose, "verbose", false, "show verbose")
+	options.register(flags)
+	driverFlags.register(flags)
+	jumpFlags.register(flags)
+	profileFlags.register(flags)
+	retryFlags.register(flags)
+	flags.Parse(args)
+
+	commands, store, err := options.store()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if store == nil {
+		log.Fatal("The commands to execute are not set")
+	}
+
+	network, err := loadNetwork(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	checkCredentials()
+	clientOpts, err := driverFlags.clientOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	telnetOpts, err := jumpFlags.telnetOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer jumpFlags.close()
+
+	//the commands are executed only over telnet
+	profiles, err := profileFlags.resolver(func(string) string {
+		return profile.TransportTelnet
+	}, func(p profile.Profile, _ string) (usecase.Client, error) {
+		return profileTelnetClient(p, telnetOpts, clientOpts), nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	retryPolicy, err := retryFlags.policy()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	executorOpts := []usecase.ExecutorOption{usecase.WithExecutorRetry(retryPolicy)}
+	if profiles != nil {
+		executorOpts = append(executorOpts, usecase.WithExecutorProfiles(profiles))
+	}
+	if verbose {
+		executorOpts = append(executorOpts, usecase.WithExecutorShowOutput())
+	}
+	executor := usecase.NewExecutor(cisco.NewClient(telnet.New(telnetOpts...), clientOpts...), commands, store, executorOpts...)
+
+	ctx, cancel := interruptibleContext()
+	defer cancel()
+
+	executor.Run(ctx, network, user, password)
+	if err := options.finish(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/export"
+)
+
+// exportOptions - flags of the output of the network shared by the crawl and the export subcommand
+type exportOptions struct {
+	format string
+	pretty bool
+	center string
+	hops   int
+
+	csvDelimiter string
+	csvBOM       bool
+	csvTable     string
+
+	ansibleFormat     string
+	ansibleSiteRegexp string
+}
+
+func (o *exportOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.format, "format", "json", "the format of the result: json, mermaid, plantuml, graphml, gexf, html, csv, ansible")
+	flags.BoolVar(&o.pretty, "pretty", false, "beautiful print of the result")
+	flags.StringVar(&o.center, "center", "", "name or ip address of the switch around which the result is limited (see -hops)")
+	flags.IntVar(&o.hops, "hops", 1, "the number of hops around the -center switch included in the result")
+	flags.StringVar(&o.csvDelimiter, "csv-delimiter", ",", "the field delimiter of the csv format. Use \"tab\" for the tab character")
+	flags.BoolVar(&o.csvBOM, "csv-bom", false, "write the UTF-8 byte order mark at the beginning of the csv format")
+	flags.StringVar(&o.csvTable, "csv-table", "all", "the table of the csv format: all, switches, links")
+	flags.StringVar(&o.ansibleFormat, "ansible-format", "yaml", "the format of the ansible inventory: yaml, ini")
+	flags.StringVar(&o.ansibleSiteRegexp, "ansible-site-regexp", "", "the regular expression whose first group extracts the site from the switch name. By default the part of the name before the first '-', '_' or '.'")
+}
+
+func (o *exportOptions) renderer() (export.Renderer, error) {
+	switch o.format {
+	case "json":
+		return export.NewJSON(o.pretty), nil
+	case "mermaid":
+		return export.NewMermaid(), nil
+	case "plantuml":
+		return export.NewPlantUML(), nil
+	case "graphml":
+		return export.NewGraphML(), nil
+	case "gexf":
+		return export.NewGEXF(), nil
+	case "html":
+		return export.NewHTML(), nil
+	case "csv":
+		return o.csvRenderer()
+	case "ansible":
+		return o.ansibleRenderer()
+	}
+
+	return nil, fmt.Errorf("unknown format of the result: %s", o.format)
+}
+
+func (o *exportOptions) csvRenderer() (export.Renderer, error) {
+	delimiter := []rune(o.csvDelimiter)
+	if o.csvDelimiter == "tab" {
+		delimiter = []rune{'\t'}
+	}
+	if len(delimiter) != 1 {
+		return nil, fmt.Errorf("the delimiter of the csv format must be a single character: %s", o.csvDelimiter)
+	}
+
+	opts := []export.CSVOption{export.WithCSVDelimiter(delimiter[0])}
+	if o.csvBOM {
+		opts = append(opts, export.WithCSVBOM())
+	}
+	switch o.csvTable {
+	case "all":
+	case "switches":
+		opts = append(opts, export.WithCSVTables(export.CSVSwitches))
+	case "links":
+		opts = append(opts, export.WithCSVTables(export.CSVLinks))
+	default:
+		return nil, fmt.Errorf("unknown table of the csv format: %s", o.csvTable)
+	}
+
+	return export.NewCSV(opts...), nil
+}
+
+func (o *exportOptions) ansibleRenderer() (export.Renderer, error) {
+	var opts []export.AnsibleOption
+	switch o.ansibleFormat {
+	case "yaml":
+		opts = append(opts, export.WithAnsibleFormat(export.AnsibleYAML))
+	case "ini":
+		opts = append(opts, export.WithAnsibleFormat(export.AnsibleINI))
+	default:
+		return nil, fmt.Errorf("unknown format of the ansible inventory: %s", o.ansibleFormat)
+	}
+
+	if o.ansibleSiteRegexp != "" {
+		siteRe, err := regexp.Compile(o.ansibleSiteRegexp)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect regular expression of the site: %w", err)
+		}
+		opts = append(opts, export.WithAnsibleSiteRegexp(siteRe))
+	}
+
+	return export.NewAnsible(opts...), nil
+}
+
+func (o *exportOptions) write(w io.Writer, network *domain.Network) error {
+	renderer, err := o.renderer()
+	if err != nil {
+		return err
+	}
+
+	if o.center != "" {
+		center, err := network.Lookup(o.center)
+		if err != nil {
+			return err
+		}
+		if network, err = network.Neighborhood(center, o.hops); err != nil {
+			return err
+		}
+	}
+
+	return renderer.Render(w, network)
+}
+
+func runExport(args []string) {
+	var options exportOptions
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	input := flags.String("input", "", "the file with the result of the crawl. If not specified, the result is read from stdin")
+	options.register(flags)
+	flags.Parse(args)
+
+	if _, err := options.renderer(); err != nil {
+		log.Fatal(err)
+	}
+
+	network, err := loadNetwork(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := options.write(os.Stdout, network); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// loadNetwork reads the saved result of the crawl. If the file name is empty, the result is read from stdin.
+func loadNetwork(fileName string) (*domain.Network, error) {
+	var data []byte
+	var err error
+	if fileName == "" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load network: %w", err)
+	}
+
+	network, err := domain.NetworkFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("load network: %w", err)
+	}
+
+	return network, nil
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/jump"
+	"github.com/vps2/cisco-switches-crawler/pkg/ip"
+	"github.com/vps2/cisco-switches-crawler/pkg/telnet"
+)
+
+// jumpOptions - flags of the jump hosts through which the switches are reached
+type jumpOptions struct {
+	url        string
+	rules      string
+	password   string
+	keyFile    string
+	knownHosts string
+
+	dialers []jump.Dialer
+}
+
+func (o *jumpOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.url, "jump", "", "the jump host of the telnet connections to all the switches: ssh://user@host[:port] or socks5://[user@]host[:port]. "+
+		"SNMP is not tunnelled, the switches polled by SNMP are reached directly")
+	flags.StringVar(&o.rules, "jump-rules", "", "the jump hosts of the subnets (separated by semicolons) taking precedence over -jump. "+
+		"Example: \"10.1.0.0/16,10.2.0.0/16=ssh://admin@bastion1;172.16.0.0/12=socks5://proxy:1080\"")
+	flags.StringVar(&o.password, "jump-password", "", "the password of the user of the jump host, not of the switches. "+
+		"If not specified for an SSH jump host without -jump-key and ssh-agent, the application will ask for a password")
+	flags.StringVar(&o.keyFile, "jump-key", "", "the private key of the user of the SSH jump host")
+	flags.StringVar(&o.knownHosts, "jump-known-hosts", "", "the known_hosts file with the keys of the SSH jump hosts (default ~/.ssh/known_hosts)")
+}
+
+// jumpRule - the jump host of the subnets
+type jumpRule struct {
+	subnets string
+	url     string
+}
+
+// telnetOptions returns the options of the telnet connection connecting through the jump hosts, if they are set.
+// Only telnet is tunnelled: the SNMP client sends the UDP datagrams to the switches directly.
+func (o *jumpOptions) telnetOptions() ([]telnet.Option, error) {
+	var rules []jumpRule
+	for _, value := range strings.Split(o.rules, ";") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		subnets, jumpURL, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("-jump-rules has an incorrect rule %s. Use subnets=url", value)
+		}
+		rules = append(rules, jumpRule{subnets: subnets, url: strings.TrimSpace(jumpURL)})
+	}
+	if o.url == "" && len(rules) == 0 {
+		return nil, nil
+	}
+
+	urls := []string{o.url}
+	for _, rule := range rules {
+		urls = append(urls, rule.url)
+	}
+	auth, err := o.auth(urls)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := jump.Direct()
+	if o.url != "" {
+		if fallback, err = o.dialer(o.url, auth); err != nil {
+			return nil, err
+		}
+	}
+	router := jump.NewRouter(fallback)
+	for _, rule := range rules {
+		filter := ip.NewFilter()
+		for _, subnet := range strings.Split(rule.subnets, ",") {
+			if err := filter.Add(strings.TrimSpace(subnet)); err != nil {
+				return nil, fmt.Errorf("-jump-rules has an incorrect value of ip addresses or incorrect format: %w", err)
+			}
+		}
+		dialer, err := o.dialer(rule.url, auth)
+		if err != nil {
+			return nil, err
+		}
+		router.Add(filter, dialer)
+	}
+
+	return []telnet.Option{telnet.WithDialer(router)}, nil
+}
+
+// dialer returns the dialer of the jump host
+func (o *jumpOptions) dialer(jumpURL string, auth jump.Auth) (jump.Dialer, error) {
+	dialer, err := jump.Parse(jumpURL, auth)
+	if err != nil {
+		return nil, err
+	}
+	o.dialers = append(o.dialers, dialer)
+
+	return dialer, nil
+}
+
+// auth returns the credentials of the jump hosts. The password is asked if an SSH jump host has no other
+// authentication method.
+func (o *jumpOptions) auth(urls []string) (jump.Auth, error) {
+	auth := jump.Auth{Password: o.password, KeyFile: o.keyFile, Agent: true, KnownHosts: o.knownHosts}
+	if auth.Password != "" || auth.KeyFile != "" || os.Getenv("SSH_AUTH_SOCK") != "" {
+		return auth, nil
+	}
+
+	for _, jumpURL := range urls {
+		if u, err := url.Parse(jumpURL); err == nil && strings.EqualFold(u.Scheme, jump.SchemeSSH) {
+			if _, ok := u.User.Password(); ok {
+				continue
+			}
+			fmt.Print("jump host password: ")
+			password, err := readUserPassword()
+			fmt.Println()
+			if err != nil {
+				return auth, err
+			}
+			auth.Password = password
+			break
+		}
+	}
+
+	return auth, nil
+}
+
+// close closes the connections to the jump hosts
+func (o *jumpOptions) close() {
+	for _, dialer := range o.dialers {
+		if closer, ok := dialer.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func runLocate(args []string) {
+	flags := flag.NewFlagSet("locate", flag.ExitOnError)
+	input := flags.String("input", "", "the file with the result of the crawl made with -hosts. If not specified, the result is read from stdin")
+	host := flags.String("host", "", "MAC address or ip address of the end host")
+	flags.Parse(args)
+
+	if *host == "" {
+		log.Fatal("The MAC address or the ip address of the host is not set")
+	}
+
+	network, err := loadNetwork(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	locations, err := network.Locate(*host)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(formatLocations(locations))
+}
+
+// formatLocations prints the host and the edge ports on which it is seen, the most probable port first
+func formatLocations(locations []domain.HostLocation) string {
+	var sb strings.Builder
+
+	host := locations[0].MAC
+	if locations[0].Address != "" {
+		host += " (" + locations[0].Address + ")"
+	}
+	sb.WriteString(fmt.Sprintf("host: %s\n", host))
+
+	for _, location := range locations {
+		sb.WriteString(fmt.Sprintf("%s (%s) %s", location.Switch.Name(), location.Switch.Address(), location.Port))
+		if location.VLAN != 0 {
+			sb.WriteString(fmt.Sprintf(", vlan %d", location.VLAN))
+		}
+		if location.Hosts > 1 {
+			sb.WriteString(fmt.Sprintf(", %d hosts on the port", location.Hosts))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func runPath(args []string) {
+	flags := flag.NewFlagSet("path", flag.ExitOnError)
+	input := flags.String("input", "", "the file with the result of the crawl. If not specified, the result is read from stdin")
+	from := flags.String("from", "", "name or ip address of the first switch")
+	to := flags.String("to", "", "name or ip address of the last switch")
+	all := flags.Bool("all", false, "show all simple paths instead of the shortest one")
+	maxHops := flags.Int("max-hops", 0, "the maximum number of hops of the paths shown with -all. 0 - without limit")
+	flags.Parse(args)
+
+	if *from == "" || *to == "" {
+		log.Fatal("Both switches of the path must be set")
+	}
+
+	network, err := loadNetwork(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fromSwitch, err := network.Lookup(*from)
+	if err != nil {
+		log.Fatal(err)
+	}
+	toSwitch, err := network.Lookup(*to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !*all {
+		path, err := network.ShortestPath(fromSwitch, toSwitch)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(formatPath(network, path))
+		return
+	}
+
+	paths, err := network.AllPaths(fromSwitch, toSwitch, *maxHops)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, path := range paths {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("path %d:\n", i+1)
+		fmt.Print(formatPath(network, path))
+	}
+}
+
+// formatPath prints the switches of the path and the ports of the links between them
+func formatPath(network *domain.Network, path []domain.Switch) string {
+	var sb strings.Builder
+	for i, sw := range path {
+		sb.WriteString(fmt.Sprintf("%s (%s)\n", sw.Name(), sw.Address()))
+		if i == len(path)-1 {
+			break
+		}
+
+		for _, link := range network.LinksBetween(sw, path[i+1]) {
+			sb.WriteString(fmt.Sprintf("    %s -> %s\n", portOrUnknown(link.FromPort()), portOrUnknown(link.ToPort())))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("hops: %d\n", len(path)-1))
+
+	return sb.String()
+}
+
+func portOrUnknown(port string) string {
+	if port == "" {
+		return "?"
+	}
+
+	return port
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/cisco"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/profile"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/snmp"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+	"github.com/vps2/cisco-switches-crawler/pkg/telnet"
+)
+
+// profileOptions - flag of the connection profiles of the switches
+type profileOptions struct {
+	file string
+}
+
+func (o *profileOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.file, "profiles", "", "the JSON file with the connection profiles of the switches matched by ip address, subnet or host name: "+
+		"port, transport, timeout, write pacing and credentials")
+}
+
+// profileClient returns the client connecting with the parameters of the profile over the transport
+type profileClient func(p profile.Profile, transport string) (usecase.Client, error)
+
+// resolver returns the profiles of the file. nil if the flag is not set.
+// The transport of the profiles without it is selected by the address of the switch.
+func (o *profileOptions) resolver(transport func(address string) string, client profileClient) (usecase.ProfileResolver, error) {
+	if o.file == "" {
+		return nil, nil
+	}
+
+	config, err := profile.Load(o.file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profileResolver{config: config, transport: transport, client: client, clients: make(map[profileKey]usecase.Client)}, nil
+}
+
+type profileKey struct {
+	index     int
+	transport string
+}
+
+// profileResolver creates the clients of the profiles on demand, one client for every profile and transport
+type profileResolver struct {
+	config    *profile.Config
+	transport func(address string) string
+	client    profileClient
+	clients   map[profileKey]usecase.Client
+}
+
+func (r *profileResolver) Resolve(address string, names ...string) (usecase.Connection, bool) {
+	p, index := r.config.Resolve(address, names...)
+	if p.Transport == "" {
+		p.Transport = r.transport(address)
+	}
+
+	key := profileKey{index: index, transport: p.Transport}
+	client, ok := r.clients[key]
+	if !ok {
+		var err error
+		if client, err = r.client(p, p.Transport); err != nil {
+			log.Println(err)
+			return usecase.Connection{}, false
+		}
+		r.clients[key] = client
+	}
+
+	return usecase.Connection{Client: client, User: p.User, Password: p.Password, Profile: p.String()}, true
+}
+
+// profileTelnetClient returns the telnet client with the port, the timeout and the write pacing of the profile
+func profileTelnetClient(p profile.Profile, telnetOpts []telnet.Option, clientOpts []cisco.Option) *cisco.Client {
+	telnetOpts = append([]telnet.Option{}, telnetOpts...)
+	if p.WritePacing != 0 {
+		telnetOpts = append(telnetOpts, telnet.WriteTimeout(time.Duration(p.WritePacing)))
+	}
+	clientOpts = append([]cisco.Option{}, clientOpts...)
+	if p.Port != 0 {
+		clientOpts = append(clientOpts, cisco.WithPort(p.Port))
+	}
+	if p.Timeout != 0 {
+		clientOpts = append(clientOpts, cisco.WithTimeout(time.Duration(p.Timeout)))
+	}
+
+	return cisco.NewClient(telnet.New(telnetOpts...), clientOpts...)
+}
+
+// profileSNMPClient returns the SNMP client of the flags with the port of the profile
+func profileSNMPClient(p profile.Profile) (*snmp.Client, error) {
+	var opts []snmp.Option
+	if p.Port != 0 {
+		opts = append(opts, snmp.WithPort(uint16(p.Port)))
+	}
+
+	return snmpFlags.client(opts...)
+}
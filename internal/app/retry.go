@@ -0,0 +1,32 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+// retryOptions - flags of the retries of the failed connections
+type retryOptions struct {
+	retries  int
+	delay    time.Duration
+	maxDelay time.Duration
+}
+
+func (o *retryOptions) register(flags *flag.FlagSet) {
+	flags.IntVar(&o.retries, "retries", 2, "the number of the retries of the connections failed with the transient errors: timeouts, refused connections, busy vty lines. "+
+		"The failed authentication is not retried")
+	flags.DurationVar(&o.delay, "retry-delay", 5*time.Second, "the delay before the first retry, doubled for every next one with a random jitter")
+	flags.DurationVar(&o.maxDelay, "retry-max-delay", time.Minute, "the maximum delay between the retries")
+}
+
+// policy returns the retry policy of the flags
+func (o *retryOptions) policy() (usecase.RetryPolicy, error) {
+	if o.retries < 0 || o.delay < 0 || o.maxDelay < 0 {
+		return usecase.RetryPolicy{}, errors.New("the retries and their delays must not be negative")
+	}
+
+	return usecase.RetryPolicy{Retries: o.retries, Delay: o.delay, MaxDelay: o.maxDelay}, nil
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/snmp"
+	"github.com/vps2/cisco-switches-crawler/pkg/ip"
+)
+
+const (
+	backendTelnet = "telnet"
+	backendSNMP   = "snmp"
+)
+
+// snmpOptions - flags of the SNMP backend
+type snmpOptions struct {
+	backend      string
+	include      string
+	version      string
+	community    string
+	user         string
+	authProtocol string
+	authPassword string
+	privProtocol string
+	privPassword string
+}
+
+func (o *snmpOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.backend, "backend", backendTelnet, "the way to get the neighbors of the switches: telnet or snmp (CDP-MIB and LLDP-MIB, read-only access is enough)")
+	flags.StringVar(&o.include, "snmp-include", "", "ip addresses (separated by commas) of the switches polled over SNMP regardless of -backend. Example: [192.168.1.1,192.168.2.0/24]")
+	flags.StringVar(&o.version, "snmp-version", string(snmp.Version2c), "the version of SNMP: 2c or 3")
+	flags.StringVar(&o.community, "snmp-community", "public", "the community of SNMP v2c")
+	flags.StringVar(&o.user, "snmp-user", "", "the name of the user of SNMP v3")
+	flags.StringVar(&o.authProtocol, "snmp-auth-protocol", "SHA", "the authentication protocol of SNMP v3: MD5, SHA, SHA224, SHA256, SHA384 or SHA512")
+	flags.StringVar(&o.authPassword, "snmp-auth-password", "", "the authentication password of SNMP v3. If not specified, the messages are not authenticated")
+	flags.StringVar(&o.privProtocol, "snmp-priv-protocol", "AES", "the privacy protocol of SNMP v3: DES, AES, AES192, AES256, AES192C or AES256C")
+	flags.StringVar(&o.privPassword, "snmp-priv-password", "", "the privacy password of SNMP v3. If not specified, the messages are not encrypted")
+}
+
+// enabled reports whether the neighbors are requested over SNMP
+func (o *snmpOptions) enabled() (bool, error) {
+	switch o.backend {
+	case backendTelnet:
+		return false, nil
+	case backendSNMP:
+		return true, nil
+	}
+
+	return false, fmt.Errorf("unknown backend %s. Use telnet or snmp", o.backend)
+}
+
+// filter returns the filter of the switches polled over SNMP by -snmp-include. nil if the flag is not set.
+func (o *snmpOptions) filter() (*ip.Filter, error) {
+	if o.include == "" {
+		return nil, nil
+	}
+
+	filter := ip.NewFilter()
+	for _, address := range strings.Split(o.include, ",") {
+		if err := filter.Add(strings.TrimSpace(address)); err != nil {
+			return nil, fmt.Errorf("-snmp-include has an incorrect value of ip addresses or incorrect format: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// client returns the SNMP client configured by the flags and the options
+func (o *snmpOptions) client(opts ...snmp.Option) (*snmp.Client, error) {
+	switch snmp.Version(o.version) {
+	case snmp.Version2c:
+		return snmp.NewClient(append([]snmp.Option{snmp.WithCommunity(o.community)}, opts...)...), nil
+	case snmp.Version3:
+		if o.user == "" {
+			return nil, errors.New("the user of SNMP v3 is not set. Use -snmp-user")
+		}
+		if err := snmp.CheckProtocols(o.authProtocol, o.privProtocol); err != nil {
+			return nil, err
+		}
+		return snmp.NewClient(append([]snmp.Option{snmp.WithV3(o.user, o.authProtocol, o.authPassword, o.privProtocol, o.privPassword)}, opts...)...), nil
+	}
+
+	return nil, fmt.Errorf("unknown version of SNMP %s. Use 2c or 3", o.version)
+}
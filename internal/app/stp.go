@@ -0,0 +1,43 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// formatSpanningTree prints the root bridge of every spanning tree instance and the blocked links.
+// An instance with several roots is split into separate spanning tree domains.
+func formatSpanningTree(network *domain.Network) string {
+	var sb strings.Builder
+
+	sb.WriteString("Spanning tree roots:\n")
+	roots := network.STPRoots()
+	if len(roots) == 0 {
+		sb.WriteString("    none\n")
+	}
+	for _, root := range roots {
+		bridge := fmt.Sprintf("%s (not a crawled switch)", root.MAC)
+		if root.Found {
+			bridge = fmt.Sprintf("%s (%s)", root.Switch.Name(), root.Switch.Address())
+		}
+		sb.WriteString(fmt.Sprintf("    %s: %s, priority %d, seen by %d switches\n", root.Instance, bridge, root.Priority, len(root.SeenBy)))
+	}
+
+	sb.WriteString("Links blocked by the spanning tree:\n")
+	blockedLinks := network.BlockedLinks()
+	if len(blockedLinks) == 0 {
+		sb.WriteString("    none\n")
+	}
+	for _, blocked := range blockedLinks {
+		from, _ := network.Switch(blocked.Link.FromAddress())
+		to, _ := network.Switch(blocked.Link.ToAddress())
+		sb.WriteString(fmt.Sprintf("    %s (%s) %s <-> %s %s (%s): %s\n",
+			from.Name(), from.Address(), portOrUnknown(blocked.Link.FromPort()),
+			portOrUnknown(blocked.Link.ToPort()), to.Name(), to.Address(),
+			strings.Join(blocked.Instances, ", ")))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// formatSummary prints the number of switches by the crawl status, the crawled parts of the network
+// and the parts left unreached behind the failed and discarded switches
+func formatSummary(network *domain.Network) string {
+	statuses := make(map[domain.Status]int)
+	for _, sw := range network.Switches() {
+		statuses[sw.Status()]++
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Switches: %d (crawled: %d, failed: %d, discarded: %d, not crawled: %d)\n",
+		network.Len(), statuses[domain.StatusCrawled], statuses[domain.StatusFailed],
+		statuses[domain.StatusDiscarded], statuses[domain.StatusNotCrawled]))
+
+	var mismatches []string
+	for _, sw := range network.Switches() {
+		if sw.DNSMismatch() {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s) is %s in DNS", sw.Name(), sw.Address(), sw.DNSName()))
+		}
+	}
+	if len(mismatches) > 0 {
+		sb.WriteString(fmt.Sprintf("DNS name mismatches: %d\n", len(mismatches)))
+		for _, mismatch := range mismatches {
+			sb.WriteString("    " + mismatch + "\n")
+		}
+	}
+
+	var reached, unreached []domain.Component
+	for _, component := range network.Components() {
+		if component.Crawled() > 0 {
+			reached = append(reached, component)
+		} else {
+			unreached = append(unreached, component)
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Connected components: %d\n", len(reached)))
+	for i, component := range reached {
+		sb.WriteString(fmt.Sprintf("    %d. %d switches, seed: %s (%s)\n", i+1, component.Len(), component.Seed.Name(), component.Seed.Address()))
+	}
+	if len(unreached) > 0 {
+		sb.WriteString(fmt.Sprintf("Unreached parts: %d\n", len(unreached)))
+		for i, component := range unreached {
+			sb.WriteString(fmt.Sprintf("    %d. %d switches (%s), seed: %s (%s)\n",
+				i+1, component.Len(), formatStatuses(component.Switches), component.Seed.Name(), component.Seed.Address()))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatStatuses counts the switches by the crawl status, e.g. "failed: 1, discarded: 2"
+func formatStatuses(switches []domain.Switch) string {
+	statuses := make(map[domain.Status]int)
+	for _, sw := range switches {
+		statuses[sw.Status()]++
+	}
+
+	var counts []string
+	for _, status := range []domain.Status{domain.StatusFailed, domain.StatusDiscarded, domain.StatusNotCrawled} {
+		if statuses[status] > 0 {
+			counts = append(counts, fmt.Sprintf("%s: %d", strings.ReplaceAll(string(status), "_", " "), statuses[status]))
+		}
+	}
+
+	return strings.Join(counts, ", ")
+}
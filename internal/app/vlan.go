@@ -0,0 +1,71 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func runVLAN(args []string) {
+	var options exportOptions
+
+	flags := flag.NewFlagSet("vlan", flag.ExitOnError)
+	input := flags.String("input", "", "the file with the result of the crawl made with -vlans. If not specified, the result is read from stdin")
+	id := flags.Int("id", 0, "the VLAN whose topology is shown")
+	options.register(flags)
+	flags.Parse(args)
+
+	if *id <= 0 {
+		log.Fatal("The VLAN is not set")
+	}
+	if _, err := options.renderer(); err != nil {
+		log.Fatal(err)
+	}
+
+	network, err := loadNetwork(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := options.write(os.Stdout, network.VLANTopology(*id)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// formatVLANMismatches prints the links whose ends disagree about the native or the allowed VLANs
+func formatVLANMismatches(network *domain.Network) string {
+	var sb strings.Builder
+
+	sb.WriteString("VLAN mismatches:\n")
+	mismatches := network.VLANMismatches()
+	if len(mismatches) == 0 {
+		sb.WriteString("    none\n")
+	}
+	for _, mismatch := range mismatches {
+		from, _ := network.Switch(mismatch.Link.FromAddress())
+		to, _ := network.Switch(mismatch.Link.ToAddress())
+		sb.WriteString(fmt.Sprintf("    %s (%s) %s <-> %s %s (%s): ",
+			from.Name(), from.Address(), portOrUnknown(mismatch.Link.FromPort()),
+			portOrUnknown(mismatch.Link.ToPort()), to.Name(), to.Address()))
+
+		switch mismatch.Kind {
+		case domain.MismatchNative:
+			sb.WriteString(fmt.Sprintf("native vlan %d <-> %d\n", mismatch.Link.FromVLANs().Native, mismatch.Link.ToVLANs().Native))
+		case domain.MismatchAllowed:
+			var parts []string
+			if len(mismatch.FromOnly) > 0 {
+				parts = append(parts, fmt.Sprintf("vlans %s allowed only on %s", mismatch.FromOnly, from.Name()))
+			}
+			if len(mismatch.ToOnly) > 0 {
+				parts = append(parts, fmt.Sprintf("vlans %s allowed only on %s", mismatch.ToOnly, to.Name()))
+			}
+			sb.WriteString(strings.Join(parts, ", ") + "\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"sort"
+
+	"github.com/vps2/cisco-switches-crawler/pkg/queue"
+)
+
+// Component - connected part of the network
+type Component struct {
+	Switches []Switch //ordered by address
+	Seed     Switch   //the switch from which the crawl of the component can be started
+}
+
+func (c Component) Len() int {
+	return len(c.Switches)
+}
+
+// Crawled returns the number of switches of the component whose neighbors are known
+func (c Component) Crawled() int {
+	crawled := 0
+	for _, sw := range c.Switches {
+		if sw.Status() == StatusCrawled {
+			crawled++
+		}
+	}
+
+	return crawled
+}
+
+// Components groups the switches into the islands of the crawl. The crawled switches are connected only by the links
+// between the crawled switches, so the failed, discarded and not crawled switches are the boundaries of the islands.
+// The switches left behind the boundaries form the unreached parts, connected by the links between themselves,
+// whose seeds may start another crawl. The crawled parts go first, the largest parts first.
+func (n *Network) Components() []Component {
+	visited := make(map[string]bool, len(n.graph))
+
+	var components []Component
+	for _, sw := range n.Switches() {
+		if visited[sw.Address()] {
+			continue
+		}
+		visited[sw.Address()] = true
+
+		var component Component
+		q := queue.New[string]()
+		q.Push(sw.Address())
+		for !q.IsEmpty() {
+			curr := q.Pop()
+			component.Switches = append(component.Switches, n.switches[curr])
+
+			crawled := n.crawled(curr)
+			for _, neighbor := range n.sortedNeighbors(curr) {
+				if n.crawled(neighbor) != crawled {
+					continue
+				}
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					q.Push(neighbor)
+				}
+			}
+		}
+
+		sort.Slice(component.Switches, func(i, j int) bool {
+			return lessAddress(component.Switches[i].Address(), component.Switches[j].Address())
+		})
+		component.Seed = chooseSeed(component.Switches)
+		components = append(components, component)
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		reachedI, reachedJ := components[i].Crawled() > 0, components[j].Crawled() > 0
+		if reachedI != reachedJ {
+			return reachedI
+		}
+		return components[i].Len() > components[j].Len()
+	})
+
+	return components
+}
+
+// crawled reports whether the neighbors of the switch are known
+func (n *Network) crawled(address string) bool {
+	sw := n.switches[address]
+	return sw.Status() == StatusCrawled
+}
+
+// chooseSeed prefers the switch that has already been crawled successfully, then the one that has not been polled yet.
+// Failed and discarded switches are the last resort.
+func chooseSeed(switches []Switch) Switch {
+	priority := map[Status]int{StatusCrawled: 0, StatusNotCrawled: 1, StatusFailed: 2, StatusDiscarded: 3}
+
+	seed := switches[0]
+	for _, sw := range switches[1:] {
+		if p, ok := priority[sw.Status()]; ok && p < priority[seed.Status()] {
+			seed = sw
+		}
+	}
+
+	return seed
+}
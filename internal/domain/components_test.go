@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestComponents(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	//the failed sw3 splits the crawled switches: sw1-sw2 are reached over the ring, sw5 only over sw3
+	for i, status := range map[int]domain.Status{
+		0: domain.StatusCrawled,
+		1: domain.StatusCrawled,
+		2: domain.StatusFailed,
+		3: domain.StatusDiscarded,
+		4: domain.StatusCrawled,
+	} {
+		sw[i].SetStatus(status)
+		network.UpdateSwitch(sw[i])
+	}
+
+	components := network.Components()
+	if len(components) != 4 {
+		t.Fatalf("Network.Components() returned %d components, want %d", len(components), 4)
+	}
+
+	tests := []struct {
+		name    string
+		got     domain.Component
+		size    int
+		crawled int
+		seed    string
+	}{
+		{"main part", components[0], 2, 2, sw[0].Address()},
+		{"behind the failed switch", components[1], 1, 1, sw[4].Address()},
+		{"failed and discarded", components[2], 2, 0, sw[2].Address()},
+		{"never reached", components[3], 1, 0, sw[5].Address()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Len() != tt.size {
+				t.Errorf("Component.Len() = %v, want %v", tt.got.Len(), tt.size)
+			}
+			if tt.got.Crawled() != tt.crawled {
+				t.Errorf("Component.Crawled() = %v, want %v", tt.got.Crawled(), tt.crawled)
+			}
+			if tt.got.Seed.Address() != tt.seed {
+				t.Errorf("Component.Seed = %v, want %v", tt.got.Seed.Address(), tt.seed)
+			}
+		})
+	}
+}
@@ -4,8 +4,19 @@ import "errors"
 
 var (
 	ErrInvalidSwitchIPAddress = errors.New("wrong switch ip address")
+	ErrInvalidAddressFamily   = errors.New("unknown address family, use ipv4 or ipv6")
+	ErrUnresolvedHost         = errors.New("the host name has no ip addresses")
 
 	ErrEmptySwitchAddress = errors.New("empty switch IP address")
 	ErrSwitchNotInNetwork = errors.New("the switch has not been added to the network")
 	ErrLink               = errors.New("attempt to create a link with yourself")
+	ErrNoPath             = errors.New("there is no path between the switches")
+
+	ErrInvalidMACAddress = errors.New("wrong MAC address")
+	ErrHostNotFound      = errors.New("the host is not found on the edge ports of the switches")
+
+	ErrInvalidVLAN = errors.New("wrong VLAN list")
+
+	ErrAuthentication = errors.New("authentication failed")
+	ErrDeviceBusy     = errors.New("the switch has no free sessions")
 )
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MACEntry - MAC address learned by the switch on the port
+type MACEntry struct {
+	MAC  string //normalized by NormalizeMAC
+	VLAN int
+	Port string
+}
+
+// ARPEntry - ip address resolved by the switch to the MAC address
+type ARPEntry struct {
+	Address string
+	MAC     string //normalized by NormalizeMAC
+}
+
+// HostLocation - edge port of the switch on which the end host is seen
+type HostLocation struct {
+	Switch  Switch
+	Port    string
+	VLAN    int
+	MAC     string
+	Address string //ip address of the host, if it is known from the ARP tables
+	Hosts   int    //the number of MAC addresses on the port. More than one usually means an unmanaged switch or a phone.
+}
+
+// NormalizeMAC converts the MAC address in any of the common notations (0011.2233.4455, 00-11-22-33-44-55,
+// 00:11:22:33:44:55) to the lowercase colon notation
+func NormalizeMAC(mac string) (string, error) {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil {
+		return "", fmt.Errorf("normalize mac [%s]: %w", mac, ErrInvalidMACAddress)
+	}
+
+	return hw.String(), nil
+}
+
+// Locate finds the edge ports on which the host with the MAC or ip address is seen.
+// The entries learned on the links to the other switches, including their port-channels, are skipped.
+// Ports with fewer hosts go first.
+func (n *Network) Locate(macOrAddress string) ([]HostLocation, error) {
+	mac, address := n.resolveHost(macOrAddress)
+	if mac == "" {
+		return nil, fmt.Errorf("network locate [%s]: %w", macOrAddress, ErrHostNotFound)
+	}
+
+	var locations []HostLocation
+	for _, sw := range n.Switches() {
+		hosts := make(map[string]int)
+		for _, entry := range sw.MACTable() {
+			hosts[strings.ToLower(entry.Port)]++
+		}
+
+		for _, entry := range sw.MACTable() {
+			if entry.MAC != mac || n.isLinkPort(sw, entry.Port) {
+				continue
+			}
+			locations = append(locations, HostLocation{
+				Switch:  sw,
+				Port:    entry.Port,
+				VLAN:    entry.VLAN,
+				MAC:     mac,
+				Address: address,
+				Hosts:   hosts[strings.ToLower(entry.Port)],
+			})
+		}
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("network locate [%s]: %w", macOrAddress, ErrHostNotFound)
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].Hosts < locations[j].Hosts
+	})
+
+	return locations, nil
+}
+
+// resolveHost returns the MAC address and the ip address of the host. The ip address is resolved by the ARP tables.
+func (n *Network) resolveHost(macOrAddress string) (string, string) {
+	if mac, err := NormalizeMAC(macOrAddress); err == nil {
+		for _, sw := range n.Switches() {
+			for _, entry := range sw.ARPTable() {
+				if entry.MAC == mac {
+					return mac, entry.Address
+				}
+			}
+		}
+		return mac, ""
+	}
+
+	ip := net.ParseIP(macOrAddress)
+	if ip == nil {
+		return "", ""
+	}
+	for _, sw := range n.Switches() {
+		for _, entry := range sw.ARPTable() {
+			if ip.Equal(net.ParseIP(entry.Address)) {
+				return entry.MAC, entry.Address
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// isLinkPort reports whether the port of the switch connects it to another switch of the network. The neighbors
+// that were neither crawled nor discarded, e.g. the IP phones and the access points reported by CDP, are not switches:
+// the hosts behind them are seen on the access port.
+func (n *Network) isLinkPort(sw Switch, port string) bool {
+	for _, neighbor := range n.sortedNeighbors(sw.Address()) {
+		if status := n.switches[neighbor].status; status != StatusCrawled && status != StatusDiscarded {
+			continue
+		}
+		for _, link := range n.links[newLinkKey(sw.Address(), neighbor)] {
+			if link.from != sw.Address() {
+				link = link.reverse()
+			}
+			if sw.SamePort(link.fromPort, port) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// EquivalentPort compares the full and the abbreviated names of the port, e.g. GigabitEthernet0/1 and Gi0/1
+func EquivalentPort(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	typeA, numberA := splitPort(a)
+	typeB, numberB := splitPort(b)
+	if numberA != numberB || typeA == "" || typeB == "" {
+		return strings.EqualFold(a, b)
+	}
+
+	return strings.HasPrefix(typeA, typeB) || strings.HasPrefix(typeB, typeA)
+}
+
+// splitPort splits the name of the port into the lowercase type and the number
+func splitPort(port string) (string, string) {
+	port = strings.ReplaceAll(port, " ", "")
+	i := strings.IndexFunc(port, unicode.IsDigit)
+	if i < 0 {
+		return strings.ToLower(port), ""
+	}
+
+	return strings.ToLower(port[:i]), port[i:]
+}
@@ -0,0 +1,120 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestNormalizeMAC(t *testing.T) {
+	tests := []struct {
+		mac     string
+		expect  string
+		wantErr bool
+	}{
+		{"0011.22AA.bbcc", "00:11:22:aa:bb:cc", false},
+		{"00-11-22-AA-BB-CC", "00:11:22:aa:bb:cc", false},
+		{"00:11:22:aa:bb:cc", "00:11:22:aa:bb:cc", false},
+		{"192.168.1.1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mac, func(t *testing.T) {
+			mac, err := domain.NormalizeMAC(tt.mac)
+			if (err != nil) != tt.wantErr || mac != tt.expect {
+				t.Errorf("NormalizeMAC() = %q, %v, want %q", mac, err, tt.expect)
+			}
+		})
+	}
+}
+
+func TestLocate(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	//the host 00:00:00:00:00:01 is connected to sw5, the phone sw6 with the PC behind it - to sw2.
+	//The phone is reported by CDP, but it is not a switch: its connection fails.
+	for i := range sw {
+		sw[i].SetStatus(domain.StatusCrawled)
+	}
+	sw[5].SetStatus(domain.StatusFailed)
+	network.AddLink(sw[1], sw[5], domain.WithPorts("Fa0/5", "Port 1"))
+
+	//the host 00:00:00:00:00:04 is connected to sw5 and seen by sw3 on the port-channel of the uplink Gi0/2
+	sw[2].SetPortChannels([]domain.PortChannel{{Port: "Po1", Members: []string{"GigabitEthernet0/2", "GigabitEthernet0/3"}}})
+	sw[0].SetARPTable([]domain.ARPEntry{{Address: "192.168.1.100", MAC: "00:00:00:00:00:01"}})
+	sw[0].SetMACTable([]domain.MACEntry{{MAC: "00:00:00:00:00:01", VLAN: 10, Port: "GigabitEthernet0/1"}})
+	sw[1].SetMACTable([]domain.MACEntry{
+		{MAC: "00:00:00:00:00:01", VLAN: 10, Port: "Gi0/1"},
+		{MAC: "00:00:00:00:00:02", VLAN: 10, Port: "Fa0/5"},
+		{MAC: "00:00:00:00:00:03", VLAN: 20, Port: "Fa0/5"},
+	})
+	sw[2].SetMACTable([]domain.MACEntry{
+		{MAC: "00:00:00:00:00:01", VLAN: 10, Port: "Po1"},
+		{MAC: "00:00:00:00:00:04", VLAN: 10, Port: "Po1"},
+	})
+	sw[4].SetMACTable([]domain.MACEntry{
+		{MAC: "00:00:00:00:00:01", VLAN: 10, Port: "Fa0/7"},
+		{MAC: "00:00:00:00:00:04", VLAN: 10, Port: "Fa0/8"},
+	})
+	for _, s := range sw {
+		network.UpdateSwitch(s)
+	}
+
+	tests := []struct {
+		name    string
+		host    string
+		device  string
+		port    string
+		address string
+		hosts   int
+		wantErr error
+	}{
+		{"by ip address", "192.168.1.100", "sw5", "Fa0/7", "192.168.1.100", 1, nil},
+		{"by mac address", "0000.0000.0001", "sw5", "Fa0/7", "192.168.1.100", 1, nil},
+		{"several hosts on the port", "00-00-00-00-00-03", "sw2", "Fa0/5", "", 2, nil},
+		{"behind the phone", "00:00:00:00:00:02", "sw2", "Fa0/5", "", 2, nil},
+		{"behind the port-channel", "00:00:00:00:00:04", "sw5", "Fa0/8", "", 1, nil},
+		{"unknown ip address", "192.168.1.200", "", "", "", 0, domain.ErrHostNotFound},
+		{"unknown mac address", "00:00:00:00:00:09", "", "", "", 0, domain.ErrHostNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locations, err := network.Locate(tt.host)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Locate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(locations) != 1 {
+				t.Fatalf("Locate() = %v, want one location", locations)
+			}
+			location := locations[0]
+			if location.Switch.Name() != tt.device || location.Port != tt.port || location.Address != tt.address || location.Hosts != tt.hosts {
+				t.Errorf("Locate() = %s %s %s %d, want %s %s %s %d", location.Switch.Name(), location.Port, location.Address,
+					location.Hosts, tt.device, tt.port, tt.address, tt.hosts)
+			}
+		})
+	}
+}
+
+func TestHostTablesJSON(t *testing.T) {
+	network, sw := newTestNetwork()
+	sw[0].SetMACTable([]domain.MACEntry{{MAC: "00:00:00:00:00:01", VLAN: 10, Port: "Fa0/7"}})
+	sw[0].SetARPTable([]domain.ARPEntry{{Address: "192.168.1.100", MAC: "00:00:00:00:00:01"}})
+	sw[0].SetPortChannels([]domain.PortChannel{{Port: "Po1", Members: []string{"Gi0/1", "Gi0/2"}}})
+	network.UpdateSwitch(sw[0])
+
+	restored, err := domain.NetworkFromJSON(network.ToJSON())
+	if err != nil {
+		t.Fatalf("NetworkFromJSON() error = %v", err)
+	}
+	if string(restored.ToJSON()) != string(network.ToJSON()) {
+		t.Errorf("NetworkFromJSON() = %s, want %s", restored.ToJSON(), network.ToJSON())
+	}
+	if locations, err := restored.Locate("192.168.1.100"); err != nil || locations[0].Port != "Fa0/7" {
+		t.Errorf("Locate() after NetworkFromJSON() = %v, %v", locations, err)
+	}
+}
@@ -0,0 +1,303 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// discardedSuffix marks the names of the switches discarded by the filter
+const discardedSuffix = ">>>DISCARDED"
+
+type networkJSON struct {
+	Network    []switchJSON    `json:"network"`
+	Components []componentJSON `json:"components,omitempty"`
+}
+
+type switchJSON struct {
+	Name        string         `json:"name"`
+	Address     string         `json:"address"`
+	Status      Status         `json:"status,omitempty"`
+	Platform    string         `json:"platform,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	Serial      string         `json:"serial,omitempty"`
+	DNSName     string         `json:"dns_name,omitempty"`
+	DNSMismatch bool           `json:"dns_mismatch,omitempty"` //the name differs from the DNS name
+	Attempts    int            `json:"attempts,omitempty"`     //the number of the connections, including the retries
+	Error       string         `json:"error,omitempty"`
+	Depth       int            `json:"depth"`
+	Neighbors   []neighborJSON `json:"neighbors,omitempty"`
+	MACTable    []macEntryJSON `json:"mac_table,omitempty"`
+	ARPTable    []arpEntryJSON `json:"arp_table,omitempty"`
+	VLANs       []vlanJSON     `json:"vlans,omitempty"`
+	STP         []stpJSON      `json:"spanning_tree,omitempty"`
+
+	PortChannels []portChannelJSON `json:"port_channels,omitempty"`
+}
+
+type neighborJSON struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	LocalPort  string `json:"local_port,omitempty"`
+	RemotePort string `json:"remote_port,omitempty"`
+
+	LocalVLANs  *portVLANsJSON `json:"local_vlans,omitempty"`
+	RemoteVLANs *portVLANsJSON `json:"remote_vlans,omitempty"`
+}
+
+type portVLANsJSON struct {
+	Native  int    `json:"native,omitempty"`
+	Allowed string `json:"allowed,omitempty"`
+	Active  string `json:"active,omitempty"`
+}
+
+type vlanJSON struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+}
+
+type macEntryJSON struct {
+	MAC  string `json:"mac"`
+	VLAN int    `json:"vlan,omitempty"`
+	Port string `json:"port"`
+}
+
+type arpEntryJSON struct {
+	Address string `json:"address"`
+	MAC     string `json:"mac"`
+}
+
+type stpJSON struct {
+	Instance     string   `json:"instance"`
+	RootPriority int      `json:"root_priority"`
+	RootMAC      string   `json:"root_mac"`
+	BridgeMAC    string   `json:"bridge_mac,omitempty"`
+	IsRoot       bool     `json:"is_root,omitempty"`
+	RootPort     string   `json:"root_port,omitempty"`
+	BlockedPorts []string `json:"blocked_ports,omitempty"`
+}
+
+type portChannelJSON struct {
+	Port    string   `json:"port"`
+	Members []string `json:"members"`
+}
+
+type componentJSON struct {
+	Size     int      `json:"size"`
+	Crawled  int      `json:"crawled"`
+	Seed     string   `json:"seed"`
+	Switches []string `json:"switches"`
+}
+
+func (n *Network) ToJSON() []byte {
+	var doc networkJSON
+	doc.Network = make([]switchJSON, 0, len(n.switches))
+
+	for _, sw := range n.Switches() {
+		item := switchJSON{
+			Name:        jsonName(sw),
+			Address:     sw.Address(),
+			Status:      sw.Status(),
+			Platform:    sw.Platform(),
+			Version:     sw.Version(),
+			Serial:      sw.Serial(),
+			DNSName:     sw.DNSName(),
+			DNSMismatch: sw.DNSMismatch(),
+			Attempts:    sw.Attempts(),
+			Error:       sw.Failure(),
+			Depth:       sw.Depth(),
+		}
+		for _, address := range n.sortedNeighbors(sw.Address()) {
+			neighbor := n.switches[address]
+			for _, link := range n.LinksBetween(sw, neighbor) {
+				item.Neighbors = append(item.Neighbors, neighborJSON{
+					Name:        jsonName(neighbor),
+					Address:     neighbor.Address(),
+					LocalPort:   link.FromPort(),
+					RemotePort:  link.ToPort(),
+					LocalVLANs:  portVLANsToJSON(link.FromVLANs()),
+					RemoteVLANs: portVLANsToJSON(link.ToVLANs()),
+				})
+			}
+		}
+		for _, entry := range sw.MACTable() {
+			item.MACTable = append(item.MACTable, macEntryJSON{MAC: entry.MAC, VLAN: entry.VLAN, Port: entry.Port})
+		}
+		for _, entry := range sw.ARPTable() {
+			item.ARPTable = append(item.ARPTable, arpEntryJSON{Address: entry.Address, MAC: entry.MAC})
+		}
+		for _, vlan := range sw.VLANs() {
+			item.VLANs = append(item.VLANs, vlanJSON{ID: vlan.ID, Name: vlan.Name, Status: vlan.Status})
+		}
+		for _, instance := range sw.STP() {
+			item.STP = append(item.STP, stpJSON(instance))
+		}
+		for _, channel := range sw.PortChannels() {
+			item.PortChannels = append(item.PortChannels, portChannelJSON(channel))
+		}
+		doc.Network = append(doc.Network, item)
+	}
+
+	for _, component := range n.Components() {
+		item := componentJSON{Size: component.Len(), Crawled: component.Crawled(), Seed: component.Seed.Address()}
+		for _, sw := range component.Switches {
+			item.Switches = append(item.Switches, sw.Address())
+		}
+		doc.Components = append(doc.Components, item)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false) //the names of the discarded switches are written as is, e.g. SW4>>>DISCARDED
+	encoder.Encode(doc)          //the document consists only of strings and numbers, so encoding can not fail
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// jsonName returns the name of the switch in the JSON document. The names of the discarded switches are marked by the suffix.
+func jsonName(sw Switch) string {
+	if sw.Status() == StatusDiscarded {
+		return sw.Name() + discardedSuffix
+	}
+
+	return sw.Name()
+}
+
+// NetworkFromJSON restores the network from the result of ToJSON
+func NetworkFromJSON(data []byte) (*Network, error) {
+	var doc networkJSON
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("network from json: %w", err)
+	}
+
+	network := NewNetwork()
+	for _, item := range doc.Network {
+		sw, err := NewSwitch(item.Address)
+		if err != nil {
+			return nil, fmt.Errorf("network from json: %w", err)
+		}
+		sw.SetName(item.Name)
+		sw.SetPlatform(item.Platform)
+		sw.SetVersion(item.Version)
+		sw.SetSerial(item.Serial)
+		sw.SetDNSName(item.DNSName)
+		sw.SetAttempts(item.Attempts)
+		sw.SetFailure(item.Error)
+		sw.SetDepth(item.Depth)
+		if item.Status != "" {
+			sw.SetStatus(item.Status)
+		} else if len(item.Neighbors) > 0 { //the result of the crawl saved before the status of the switch appeared
+			sw.SetStatus(StatusCrawled)
+		}
+		if len(item.MACTable) > 0 {
+			entries := make([]MACEntry, 0, len(item.MACTable))
+			for _, entry := range item.MACTable {
+				entries = append(entries, MACEntry{MAC: entry.MAC, VLAN: entry.VLAN, Port: entry.Port})
+			}
+			sw.SetMACTable(entries)
+		}
+		if len(item.ARPTable) > 0 {
+			entries := make([]ARPEntry, 0, len(item.ARPTable))
+			for _, entry := range item.ARPTable {
+				entries = append(entries, ARPEntry{Address: entry.Address, MAC: entry.MAC})
+			}
+			sw.SetARPTable(entries)
+		}
+		if len(item.VLANs) > 0 {
+			vlans := make([]VLAN, 0, len(item.VLANs))
+			for _, vlan := range item.VLANs {
+				vlans = append(vlans, VLAN{ID: vlan.ID, Name: vlan.Name, Status: vlan.Status})
+			}
+			sw.SetVLANs(vlans)
+		}
+		if len(item.STP) > 0 {
+			instances := make([]STPInstance, 0, len(item.STP))
+			for _, instance := range item.STP {
+				instances = append(instances, STPInstance(instance))
+			}
+			sw.SetSTP(instances)
+		}
+		if len(item.PortChannels) > 0 {
+			channels := make([]PortChannel, 0, len(item.PortChannels))
+			for _, channel := range item.PortChannels {
+				channels = append(channels, PortChannel(channel))
+			}
+			sw.SetPortChannels(channels)
+		}
+		if strings.HasSuffix(item.Name, discardedSuffix) {
+			sw.SetName(strings.TrimSuffix(item.Name, discardedSuffix))
+			sw.SetStatus(StatusDiscarded)
+		}
+		network.AddSwitch(*sw)
+	}
+
+	for _, item := range doc.Network {
+		sw, _ := network.Switch(item.Address)
+		for _, neighborItem := range item.Neighbors {
+			if _, err := network.Switch(neighborItem.Address); err != nil {
+				neighbor, err := NewSwitch(neighborItem.Address)
+				if err != nil {
+					return nil, fmt.Errorf("network from json: %w", err)
+				}
+				neighbor.SetName(strings.TrimSuffix(neighborItem.Name, discardedSuffix))
+				network.AddSwitch(*neighbor)
+			}
+			neighbor, _ := network.Switch(neighborItem.Address)
+
+			localVLANs, err := portVLANsFromJSON(neighborItem.LocalVLANs)
+			if err != nil {
+				return nil, fmt.Errorf("network from json: %w", err)
+			}
+			remoteVLANs, err := portVLANsFromJSON(neighborItem.RemoteVLANs)
+			if err != nil {
+				return nil, fmt.Errorf("network from json: %w", err)
+			}
+
+			if err := network.AddLink(sw, neighbor, WithPorts(neighborItem.LocalPort, neighborItem.RemotePort), WithVLANs(localVLANs, remoteVLANs)); err != nil {
+				return nil, fmt.Errorf("network from json: %w", err)
+			}
+		}
+	}
+
+	return network, nil
+}
+
+func portVLANsToJSON(vlans PortVLANs) *portVLANsJSON {
+	if vlans.Native == 0 && vlans.Allowed == nil && vlans.Active == nil {
+		return nil
+	}
+
+	item := &portVLANsJSON{Native: vlans.Native}
+	if vlans.Allowed != nil {
+		item.Allowed = vlans.Allowed.String()
+	}
+	if vlans.Active != nil {
+		item.Active = vlans.Active.String()
+	}
+
+	return item
+}
+
+func portVLANsFromJSON(item *portVLANsJSON) (PortVLANs, error) {
+	var vlans PortVLANs
+	if item == nil {
+		return vlans, nil
+	}
+
+	vlans.Native = item.Native
+	var err error
+	if item.Allowed != "" {
+		if vlans.Allowed, err = ParseVLANSet(item.Allowed); err != nil {
+			return vlans, err
+		}
+	}
+	if item.Active != "" {
+		if vlans.Active, err = ParseVLANSet(item.Active); err != nil {
+			return vlans, err
+		}
+	}
+
+	return vlans, nil
+}
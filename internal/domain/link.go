@@ -0,0 +1,88 @@
+package domain
+
+import "fmt"
+
+// Link - connection between two switches
+type Link struct {
+	from      string
+	fromPort  string
+	fromVLANs PortVLANs
+	to        string
+	toPort    string
+	toVLANs   PortVLANs
+}
+
+type LinkOption func(*Link)
+
+// WithPorts sets the ports on both ends of the link
+func WithPorts(fromPort, toPort string) LinkOption {
+	return func(l *Link) {
+		l.fromPort = fromPort
+		l.toPort = toPort
+	}
+}
+
+// WithVLANs sets the VLANs of the ports on both ends of the link
+func WithVLANs(fromVLANs, toVLANs PortVLANs) LinkOption {
+	return func(l *Link) {
+		l.fromVLANs = fromVLANs
+		l.toVLANs = toVLANs
+	}
+}
+
+func (l Link) FromAddress() string {
+	return l.from
+}
+
+func (l Link) FromPort() string {
+	return l.fromPort
+}
+
+func (l Link) FromVLANs() PortVLANs {
+	return l.fromVLANs
+}
+
+func (l Link) ToAddress() string {
+	return l.to
+}
+
+func (l Link) ToPort() string {
+	return l.toPort
+}
+
+func (l Link) ToVLANs() PortVLANs {
+	return l.toVLANs
+}
+
+func (l Link) String() string {
+	return fmt.Sprintf("Link {%s %s <-> %s %s}", l.from, l.fromPort, l.to, l.toPort)
+}
+
+func (l Link) reverse() Link {
+	l.from, l.to = l.to, l.from
+	l.fromPort, l.toPort = l.toPort, l.fromPort
+	l.fromVLANs, l.toVLANs = l.toVLANs, l.fromVLANs
+
+	return l
+}
+
+// sameAs reports whether both links describe the same cable. An empty port is treated as unknown.
+func (l Link) sameAs(other Link) bool {
+	return samePort(l.fromPort, other.fromPort) && samePort(l.toPort, other.toPort)
+}
+
+// merge fills the unknown ports and VLANs of the link with the ports and VLANs of other
+func (l *Link) merge(other Link) {
+	if l.fromPort == "" {
+		l.fromPort = other.fromPort
+	}
+	if l.toPort == "" {
+		l.toPort = other.toPort
+	}
+	l.fromVLANs.merge(other.fromVLANs)
+	l.toVLANs.merge(other.toVLANs)
+}
+
+func samePort(a, b string) bool {
+	return a == "" || b == "" || a == b
+}
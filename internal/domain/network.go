@@ -3,22 +3,37 @@ package domain
 import (
 	"bytes"
 	"fmt"
-	"regexp"
-	"strings"
+	"net"
+	"sort"
 
 	"github.com/vps2/cisco-switches-crawler/pkg/set"
 )
 
-var switchRegexp = regexp.MustCompile(`\{(.*? .*?)\}`)
-
 // Network - network of switches
 type Network struct {
-	graph map[Switch]*set.Set[Switch]
+	switches map[string]Switch
+	graph    map[string]*set.Set[string]
+	links    map[linkKey][]Link
+}
+
+// linkKey identifies a pair of switches regardless of the direction of the link
+type linkKey struct {
+	a, b string
+}
+
+func newLinkKey(from, to string) linkKey {
+	if lessAddress(to, from) {
+		return linkKey{a: to, b: from}
+	}
+
+	return linkKey{a: from, b: to}
 }
 
 func NewNetwork() *Network {
 	return &Network{
-		graph: make(map[Switch]*set.Set[Switch]),
+		switches: make(map[string]Switch),
+		graph:    make(map[string]*set.Set[string]),
+		links:    make(map[linkKey][]Link),
 	}
 }
 
@@ -27,16 +42,28 @@ func (n *Network) AddSwitch(s Switch) error {
 		return fmt.Errorf("network add switch [%s]: %w", s.Address(), ErrEmptySwitchAddress)
 	}
 
-	if _, ok := n.graph[s]; !ok {
-		n.graph[s] = set.New[Switch]()
+	if _, ok := n.graph[s.Address()]; !ok {
+		n.switches[s.Address()] = s
+		n.graph[s.Address()] = set.New[string]()
 	}
 
 	return nil
 }
 
-func (n *Network) AddLink(fromSwitch, toSwitch Switch) error {
-	fromSwitchNeighbors, fromSwitchFound := n.graph[fromSwitch]
-	toSwitchNeighbors, toSwitchFound := n.graph[toSwitch]
+// UpdateSwitch replaces the attributes of the switch already added to the network
+func (n *Network) UpdateSwitch(s Switch) error {
+	if _, ok := n.switches[s.Address()]; !ok {
+		return fmt.Errorf("network update switch [%s]: %w", s.Address(), ErrSwitchNotInNetwork)
+	}
+
+	n.switches[s.Address()] = s
+
+	return nil
+}
+
+func (n *Network) AddLink(fromSwitch, toSwitch Switch, opts ...LinkOption) error {
+	fromSwitchNeighbors, fromSwitchFound := n.graph[fromSwitch.Address()]
+	toSwitchNeighbors, toSwitchFound := n.graph[toSwitch.Address()]
 
 	if !fromSwitchFound {
 		return fmt.Errorf("network add link to [%s]: %w", fromSwitch.Address(), ErrSwitchNotInNetwork)
@@ -45,64 +72,153 @@ func (n *Network) AddLink(fromSwitch, toSwitch Switch) error {
 		return fmt.Errorf("network add link to [%s]: %w", toSwitch.Address(), ErrSwitchNotInNetwork)
 	}
 
-	if fromSwitch == toSwitch {
+	if fromSwitch.Address() == toSwitch.Address() {
 		return fmt.Errorf("network add link to [%s]: %w", fromSwitch.Address(), ErrLink)
 	}
 
-	fromSwitchNeighbors.Add(toSwitch)
-	toSwitchNeighbors.Add(fromSwitch)
+	fromSwitchNeighbors.Add(toSwitch.Address())
+	toSwitchNeighbors.Add(fromSwitch.Address())
+
+	link := Link{from: fromSwitch.Address(), to: toSwitch.Address()}
+	for _, opt := range opts {
+		opt(&link)
+	}
+
+	key := newLinkKey(link.from, link.to)
+	if key.a != link.from {
+		link = link.reverse()
+	}
+
+	links := n.links[key]
+	for i := range links {
+		if links[i].sameAs(link) {
+			links[i].merge(link)
+			return nil
+		}
+	}
+	n.links[key] = append(links, link)
 
 	return nil
 }
 
+// Switch returns the switch with the specified address
+func (n *Network) Switch(address string) (Switch, error) {
+	sw, ok := n.switches[address]
+	if !ok {
+		return Switch{}, fmt.Errorf("network get switch [%s]: %w", address, ErrSwitchNotInNetwork)
+	}
+
+	return sw, nil
+}
+
+// Lookup searches for a switch by ip address or by name
+func (n *Network) Lookup(nameOrAddress string) (Switch, error) {
+	if sw, ok := n.switches[nameOrAddress]; ok {
+		return sw, nil
+	}
+
+	for _, sw := range n.Switches() {
+		if sw.Name() == nameOrAddress {
+			return sw, nil
+		}
+	}
+
+	return Switch{}, fmt.Errorf("network lookup [%s]: %w", nameOrAddress, ErrSwitchNotInNetwork)
+}
+
+// Switches returns all switches of the network ordered by address
+func (n *Network) Switches() []Switch {
+	switches := make([]Switch, 0, len(n.switches))
+	for _, sw := range n.switches {
+		switches = append(switches, sw)
+	}
+	sort.Slice(switches, func(i, j int) bool {
+		return lessAddress(switches[i].Address(), switches[j].Address())
+	})
+
+	return switches
+}
+
 func (n *Network) NeighborsOf(sw Switch) ([]Switch, error) {
-	neighbors, ok := n.graph[sw]
+	neighbors, ok := n.graph[sw.Address()]
 	if !ok {
 		return []Switch{}, fmt.Errorf("network show neighbors [%s]: %w", sw.Address(), ErrSwitchNotInNetwork)
 	}
 
-	return neighbors.ToSlice(), nil
-}
+	addresses := n.sortedNeighbors(sw.Address())
+	switches := make([]Switch, 0, neighbors.Len())
+	for _, address := range addresses {
+		switches = append(switches, n.switches[address])
+	}
 
-func (n *Network) Len() int {
-	return len(n.graph)
+	return switches, nil
 }
 
-func (n *Network) ToJSON() []byte {
-	graphSize := len(n.graph)
-	i := 0
-
-	var buf bytes.Buffer
-	buf.WriteString(`{"network": [`)
-	for k, v := range n.graph {
-		buf.WriteString("{")
-		buf.WriteString(fmt.Sprintf(`"name": "%s", "address": "%s"`, k.Name(), k.Address()))
-
-		submatches := switchRegexp.FindAllStringSubmatch(v.String(), -1)
-		size := len(submatches)
-		if size > 0 {
-			buf.WriteString(`, "neighbors": [`)
-			for j, submatch := range submatches {
-				tokens := strings.Split(submatch[1], " ")
-				buf.WriteString(fmt.Sprintf(`{"name": "%s", "address": "%s"}`, tokens[0], tokens[1]))
-				if j < size-1 {
-					buf.WriteString(",")
-				}
-			}
-			buf.WriteString("]")
+// LinksBetween returns all links between two switches. Links are directed from the first switch to the second.
+func (n *Network) LinksBetween(fromSwitch, toSwitch Switch) []Link {
+	key := newLinkKey(fromSwitch.Address(), toSwitch.Address())
+
+	links := make([]Link, 0, len(n.links[key]))
+	for _, link := range n.links[key] {
+		if link.from != fromSwitch.Address() {
+			link = link.reverse()
 		}
-		buf.WriteString("}")
+		links = append(links, link)
+	}
 
-		if i < graphSize-1 {
-			buf.WriteString(",")
+	return links
+}
+
+// Links returns all links of the network ordered by addresses of the switches
+func (n *Network) Links() []Link {
+	keys := make([]linkKey, 0, len(n.links))
+	for key := range n.links {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].a != keys[j].a {
+			return lessAddress(keys[i].a, keys[j].a)
 		}
-		i++
+		return lessAddress(keys[i].b, keys[j].b)
+	})
+
+	var links []Link
+	for _, key := range keys {
+		links = append(links, n.links[key]...)
 	}
-	buf.WriteString("]}")
 
-	return buf.Bytes()
+	return links
+}
+
+func (n *Network) Len() int {
+	return len(n.graph)
 }
 
 func (n *Network) String() string {
 	return string(n.ToJSON())
 }
+
+// sortedNeighbors returns addresses of the neighbors in a stable order, so that graph traversals are reproducible
+func (n *Network) sortedNeighbors(address string) []string {
+	neighbors, ok := n.graph[address]
+	if !ok {
+		return nil
+	}
+
+	addresses := neighbors.ToSlice()
+	sort.Slice(addresses, func(i, j int) bool {
+		return lessAddress(addresses[i], addresses[j])
+	})
+
+	return addresses
+}
+
+// lessAddress compares ip addresses numerically, so that 192.168.1.2 goes before 192.168.1.10
+func lessAddress(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a < b
+	}
+
+	return bytes.Compare(ipA.To16(), ipB.To16()) < 0
+}
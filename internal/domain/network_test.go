@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/vps2/cisco-switches-crawler/internal/domain"
@@ -71,3 +72,36 @@ func TestNeighborsOf(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkFromJSON(t *testing.T) {
+	network, _ := newTestNetwork()
+	sw, _ := network.Switch("192.168.1.1")
+	sw.SetDNSName("sw1-old.corp.local.")
+	network.UpdateSwitch(sw)
+	failed, _ := network.Switch("192.168.1.2")
+	failed.SetStatus(domain.StatusFailed)
+	failed.SetAttempts(3)
+	failed.SetFailure("client connect [192.168.1.2]: connection refused")
+	network.UpdateSwitch(failed)
+	discarded, _ := network.Switch("192.168.1.3")
+	discarded.SetStatus(domain.StatusDiscarded)
+	network.UpdateSwitch(discarded)
+	if !strings.Contains(string(network.ToJSON()), `"name":"`+discarded.Name()+`>>>DISCARDED"`) {
+		t.Errorf("Network.ToJSON() = %s, want the name of the discarded switch marked", network.ToJSON())
+	}
+	if !strings.Contains(string(network.ToJSON()), `"dns_name":"sw1-old.corp.local","dns_mismatch":true`) {
+		t.Errorf("Network.ToJSON() = %s, want the mismatch of the DNS name", network.ToJSON())
+	}
+
+	restored, err := domain.NetworkFromJSON(network.ToJSON())
+	if err != nil {
+		t.Fatalf("NetworkFromJSON() error = %v", err)
+	}
+
+	if got, want := string(restored.ToJSON()), string(network.ToJSON()); got != want {
+		t.Errorf("NetworkFromJSON() = %v, want %v", got, want)
+	}
+	if got, _ := restored.Switch("192.168.1.3"); got.Name() != discarded.Name() || got.Status() != domain.StatusDiscarded {
+		t.Errorf("NetworkFromJSON() discarded switch = %v %v, want %v %v", got.Name(), got.Status(), discarded.Name(), domain.StatusDiscarded)
+	}
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vps2/cisco-switches-crawler/pkg/queue"
+	"github.com/vps2/cisco-switches-crawler/pkg/set"
+)
+
+// ShortestPath returns the sequence of switches with the least number of hops between two switches
+func (n *Network) ShortestPath(from, to Switch) ([]Switch, error) {
+	if err := n.checkEnds(from, to); err != nil {
+		return nil, fmt.Errorf("network shortest path: %w", err)
+	}
+
+	previous := map[string]string{from.Address(): ""}
+	q := queue.New[string]()
+	q.Push(from.Address())
+
+	for !q.IsEmpty() {
+		curr := q.Pop()
+		if curr == to.Address() {
+			break
+		}
+
+		for _, neighbor := range n.sortedNeighbors(curr) {
+			if _, ok := previous[neighbor]; ok {
+				continue
+			}
+			previous[neighbor] = curr
+			q.Push(neighbor)
+		}
+	}
+
+	if _, ok := previous[to.Address()]; !ok {
+		return nil, fmt.Errorf("network shortest path [%s - %s]: %w", from.Address(), to.Address(), ErrNoPath)
+	}
+
+	var path []Switch
+	for address := to.Address(); address != ""; address = previous[address] {
+		path = append([]Switch{n.switches[address]}, path...)
+	}
+
+	return path, nil
+}
+
+// AllPaths returns all simple paths between two switches that consist of no more than maxHops hops.
+// If maxHops is not positive, the length of the paths is not limited.
+// The paths are ordered by the number of hops.
+func (n *Network) AllPaths(from, to Switch, maxHops int) ([][]Switch, error) {
+	if err := n.checkEnds(from, to); err != nil {
+		return nil, fmt.Errorf("network all paths: %w", err)
+	}
+
+	var paths [][]Switch
+	visited := set.New[string]()
+	path := []string{from.Address()}
+	visited.Add(from.Address())
+
+	var walk func(curr string)
+	walk = func(curr string) {
+		if curr == to.Address() {
+			found := make([]Switch, 0, len(path))
+			for _, address := range path {
+				found = append(found, n.switches[address])
+			}
+			paths = append(paths, found)
+			return
+		}
+		if maxHops > 0 && len(path)-1 >= maxHops {
+			return
+		}
+
+		for _, neighbor := range n.sortedNeighbors(curr) {
+			if visited.Has(neighbor) {
+				continue
+			}
+			visited.Add(neighbor)
+			path = append(path, neighbor)
+			walk(neighbor)
+			path = path[:len(path)-1]
+			visited.Remove(neighbor)
+		}
+	}
+	walk(from.Address())
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("network all paths [%s - %s]: %w", from.Address(), to.Address(), ErrNoPath)
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return len(paths[i]) < len(paths[j])
+	})
+
+	return paths, nil
+}
+
+// HopCount returns the number of links on the shortest path between two switches
+func (n *Network) HopCount(from, to Switch) (int, error) {
+	path, err := n.ShortestPath(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(path) - 1, nil
+}
+
+func (n *Network) checkEnds(from, to Switch) error {
+	if _, ok := n.graph[from.Address()]; !ok {
+		return fmt.Errorf("[%s]: %w", from.Address(), ErrSwitchNotInNetwork)
+	}
+	if _, ok := n.graph[to.Address()]; !ok {
+		return fmt.Errorf("[%s]: %w", to.Address(), ErrSwitchNotInNetwork)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// newTestNetwork creates a network with a ring sw1-sw2-sw3-sw4-sw1 and a tail sw3-sw5
+func newTestNetwork() (*domain.Network, []domain.Switch) {
+	var switches []domain.Switch
+	network := domain.NewNetwork()
+	for i, address := range []string{"192.168.1.1", "192.168.1.2", "192.168.1.3", "192.168.1.4", "192.168.1.5", "192.168.1.6"} {
+		sw, _ := domain.NewSwitch(address)
+		sw.SetName("sw" + strconv.Itoa(i+1))
+		network.AddSwitch(*sw)
+		switches = append(switches, *sw)
+	}
+
+	network.AddLink(switches[0], switches[1], domain.WithPorts("Gi0/1", "Gi0/24"))
+	network.AddLink(switches[1], switches[2], domain.WithPorts("Gi0/1", "Gi0/24"))
+	network.AddLink(switches[2], switches[3], domain.WithPorts("Gi0/1", "Gi0/24"))
+	network.AddLink(switches[3], switches[0], domain.WithPorts("Gi0/1", "Gi0/2"))
+	network.AddLink(switches[2], switches[4], domain.WithPorts("Gi0/2", "Fa0/1"))
+
+	return network, switches
+}
+
+func TestShortestPath(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	tests := []struct {
+		name    string
+		from    domain.Switch
+		to      domain.Switch
+		expect  []domain.Switch
+		wantErr error
+	}{
+		{"same switch", sw[0], sw[0], []domain.Switch{sw[0]}, nil},
+		{"direct neighbors", sw[0], sw[1], []domain.Switch{sw[0], sw[1]}, nil},
+		{"over the ring", sw[0], sw[2], []domain.Switch{sw[0], sw[1], sw[2]}, nil},
+		{"tail", sw[3], sw[4], []domain.Switch{sw[3], sw[2], sw[4]}, nil},
+		{"isolated switch", sw[0], sw[5], nil, domain.ErrNoPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := network.ShortestPath(tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Network.ShortestPath() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("Network.ShortestPath() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestAllPaths(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	tests := []struct {
+		name    string
+		maxHops int
+		expect  [][]domain.Switch
+	}{
+		{
+			"without limit",
+			0,
+			[][]domain.Switch{{sw[0], sw[1], sw[2], sw[4]}, {sw[0], sw[3], sw[2], sw[4]}},
+		},
+		{
+			"too short limit",
+			2,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := network.AllPaths(sw[0], sw[4], tt.maxHops); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("Network.AllPaths() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestHopCount(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	if got, _ := network.HopCount(sw[1], sw[4]); got != 2 {
+		t.Errorf("Network.HopCount() = %v, want %v", got, 2)
+	}
+	if _, err := network.HopCount(sw[1], sw[5]); !errors.Is(err, domain.ErrNoPath) {
+		t.Errorf("Network.HopCount() error = %v, want %v", err, domain.ErrNoPath)
+	}
+}
+
+func TestLinksBetween(t *testing.T) {
+	network, sw := newTestNetwork()
+	network.AddLink(sw[1], sw[0], domain.WithPorts("Gi0/24", "Gi0/1")) //the same cable seen from the other side
+	network.AddLink(sw[1], sw[0], domain.WithPorts("Gi0/23", "Gi0/3"))
+
+	got := network.LinksBetween(sw[1], sw[0])
+	if len(got) != 2 {
+		t.Fatalf("Network.LinksBetween() returned %d links, want %d", len(got), 2)
+	}
+	if got[0].FromPort() != "Gi0/24" || got[0].ToPort() != "Gi0/1" {
+		t.Errorf("Network.LinksBetween() = %v, want ports Gi0/24 <-> Gi0/1", got[0])
+	}
+}
+
+func TestNeighborhood(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	tests := []struct {
+		name   string
+		center domain.Switch
+		hops   int
+		expect []domain.Switch
+	}{
+		{"center only", sw[4], 0, []domain.Switch{sw[4]}},
+		{"one hop", sw[4], 1, []domain.Switch{sw[2], sw[4]}},
+		{"two hops", sw[4], 2, []domain.Switch{sw[1], sw[2], sw[3], sw[4]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := network.Neighborhood(tt.center, tt.hops)
+			if err != nil {
+				t.Fatalf("Network.Neighborhood() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Switches(), tt.expect) {
+				t.Errorf("Network.Neighborhood() = %v, want %v", got.Switches(), tt.expect)
+			}
+		})
+	}
+
+	//sw1 is three hops away, so its links to sw2 and sw4 are cut off
+	sub, _ := network.Neighborhood(sw[4], 2)
+	if got := len(sub.Links()); got != 3 {
+		t.Errorf("Network.Neighborhood() has %d links, want %d", got, 3)
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+// PortChannel - port-channel (EtherChannel) of the switch. The MAC address table, the trunks and the spanning tree
+// report the port-channel, while CDP and LLDP report its member ports.
+type PortChannel struct {
+	Port    string   //e.g. Po1
+	Members []string //e.g. Gi1/0/1, Gi1/0/2
+}
+
+func (s *Switch) SetPortChannels(channels []PortChannel) {
+	s.portChannels = channels
+}
+
+// PortChannels returns the port-channels of the switch, if they were collected
+func (s *Switch) PortChannels() []PortChannel {
+	return s.portChannels
+}
+
+// LogicalPort returns the port-channel of the member port or the port itself if it is not a member of a port-channel
+func (s *Switch) LogicalPort(port string) string {
+	for _, channel := range s.portChannels {
+		for _, member := range channel.Members {
+			if EquivalentPort(member, port) {
+				return channel.Port
+			}
+		}
+	}
+
+	return port
+}
+
+// SamePort reports whether the names denote the same port of the switch, e.g. the member port reported by CDP
+// and its port-channel reported by the MAC address table
+func (s *Switch) SamePort(a, b string) bool {
+	return EquivalentPort(s.LogicalPort(a), s.LogicalPort(b))
+}
@@ -0,0 +1,147 @@
+package domain
+
+import "sort"
+
+// CutSwitch - switch whose failure splits the network into several parts
+type CutSwitch struct {
+	Switch Switch
+	Parts  []int //sizes of the parts of the network left after the failure of the switch, in descending order
+}
+
+// CutLink - link whose failure splits the network into two parts
+type CutLink struct {
+	Link     Link
+	FromPart int //the number of switches left on the side of the first switch of the link
+	ToPart   int //the number of switches left on the side of the second switch of the link
+}
+
+// ArticulationPoints returns the switches whose failure would partition the network
+func (n *Network) ArticulationPoints() []CutSwitch {
+	var cuts []CutSwitch
+	n.lowLinks(func(sw string, parts []int) {
+		sort.Sort(sort.Reverse(sort.IntSlice(parts)))
+		cuts = append(cuts, CutSwitch{Switch: n.switches[sw], Parts: parts})
+	}, nil)
+
+	sort.Slice(cuts, func(i, j int) bool {
+		return lessAddress(cuts[i].Switch.Address(), cuts[j].Switch.Address())
+	})
+
+	return cuts
+}
+
+// Bridges returns the links whose failure would partition the network.
+// Switches connected by several parallel links are not considered a single point of failure.
+func (n *Network) Bridges() []CutLink {
+	var cuts []CutLink
+	n.lowLinks(nil, func(from, to string, fromPart, toPart int) {
+		links := n.links[newLinkKey(from, to)]
+		if len(links) != 1 {
+			return
+		}
+
+		link := links[0]
+		if link.from != from {
+			link = link.reverse()
+		}
+		cuts = append(cuts, CutLink{Link: link, FromPart: fromPart, ToPart: toPart})
+	})
+
+	sort.Slice(cuts, func(i, j int) bool {
+		if cuts[i].Link.from != cuts[j].Link.from {
+			return lessAddress(cuts[i].Link.from, cuts[j].Link.from)
+		}
+		return lessAddress(cuts[i].Link.to, cuts[j].Link.to)
+	})
+
+	return cuts
+}
+
+// lowLinks walks the network in depth (Tarjan's algorithm) and reports articulation points and bridges
+// together with the sizes of the parts the network would split into
+func (n *Network) lowLinks(onCutSwitch func(sw string, parts []int), onCutLink func(from, to string, fromPart, toPart int)) {
+	type cutSwitch struct {
+		sw     string
+		isRoot bool
+		cutOff []int //sizes of the subtrees separated from the rest of the component
+	}
+	type cutLink struct {
+		from, to string
+		toPart   int
+	}
+
+	order := make(map[string]int, len(n.graph))
+	low := make(map[string]int, len(n.graph))
+	size := make(map[string]int, len(n.graph)) //the number of switches in the dfs subtree
+	counter := 0
+
+	var cutSwitches []cutSwitch
+	var cutLinks []cutLink
+
+	var walk func(curr, parent string)
+	walk = func(curr, parent string) {
+		counter++
+		order[curr], low[curr], size[curr] = counter, counter, 1
+
+		var cutOff []int
+		for _, neighbor := range n.sortedNeighbors(curr) {
+			if neighbor == parent {
+				continue
+			}
+			if _, ok := order[neighbor]; ok {
+				if order[neighbor] < low[curr] {
+					low[curr] = order[neighbor]
+				}
+				continue
+			}
+
+			walk(neighbor, curr)
+			size[curr] += size[neighbor]
+			if low[neighbor] < low[curr] {
+				low[curr] = low[neighbor]
+			}
+
+			if low[neighbor] >= order[curr] {
+				cutOff = append(cutOff, size[neighbor])
+			}
+			if low[neighbor] > order[curr] {
+				cutLinks = append(cutLinks, cutLink{from: curr, to: neighbor, toPart: size[neighbor]})
+			}
+		}
+
+		//the root of the dfs tree is an articulation point only if it has more than one subtree
+		isRoot := parent == ""
+		if (!isRoot && len(cutOff) > 0) || (isRoot && len(cutOff) > 1) {
+			cutSwitches = append(cutSwitches, cutSwitch{sw: curr, isRoot: isRoot, cutOff: cutOff})
+		}
+	}
+
+	for _, sw := range n.Switches() {
+		if _, ok := order[sw.Address()]; ok {
+			continue
+		}
+
+		cutSwitches, cutLinks = cutSwitches[:0], cutLinks[:0]
+		walk(sw.Address(), "")
+		componentSize := size[sw.Address()]
+
+		for _, cut := range cutSwitches {
+			parts := cut.cutOff
+			if !cut.isRoot {
+				rest := componentSize - 1
+				for _, part := range cut.cutOff {
+					rest -= part
+				}
+				parts = append(parts, rest)
+			}
+			if onCutSwitch != nil {
+				onCutSwitch(cut.sw, parts)
+			}
+		}
+		for _, cut := range cutLinks {
+			if onCutLink != nil {
+				onCutLink(cut.from, cut.to, componentSize-cut.toPart, cut.toPart)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package domain_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestArticulationPoints(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	got := network.ArticulationPoints()
+	expect := []domain.CutSwitch{{Switch: sw[2], Parts: []int{3, 1}}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Network.ArticulationPoints() = %v, want %v", got, expect)
+	}
+
+	//the chain sw5-sw6 hangs on sw5
+	network.AddLink(sw[4], sw[5])
+	got = network.ArticulationPoints()
+	expect = []domain.CutSwitch{
+		{Switch: sw[2], Parts: []int{3, 2}},
+		{Switch: sw[4], Parts: []int{4, 1}},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Network.ArticulationPoints() = %v, want %v", got, expect)
+	}
+}
+
+func TestBridges(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	got := network.Bridges()
+	if len(got) != 1 {
+		t.Fatalf("Network.Bridges() = %v, want one link", got)
+	}
+	if got[0].Link.FromAddress() != sw[2].Address() || got[0].Link.ToAddress() != sw[4].Address() {
+		t.Errorf("Network.Bridges() link = %v, want %v <-> %v", got[0].Link, sw[2].Address(), sw[4].Address())
+	}
+	if got[0].FromPart != 4 || got[0].ToPart != 1 {
+		t.Errorf("Network.Bridges() parts = %d, %d, want %d, %d", got[0].FromPart, got[0].ToPart, 4, 1)
+	}
+
+	//the second uplink of sw5 removes the single point of failure
+	network.AddLink(sw[4], sw[2], domain.WithPorts("Fa0/2", "Gi0/3"))
+	if got := network.Bridges(); len(got) != 0 {
+		t.Errorf("Network.Bridges() = %v, want none", got)
+	}
+}
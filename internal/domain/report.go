@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// DeviceReport - information about the switch returned by the backend that polled it
+type DeviceReport struct {
+	Name      string
+	Address   string
+	Platform  string
+	Version   string
+	Serial    string //the serial number of the chassis, if the backend can get it
+	Neighbors []NeighborReport
+}
+
+// NeighborReport - neighbor of the switch and the ports of the link to it
+type NeighborReport struct {
+	Name       string
+	Address    string   //the first IPv4 address of the neighbor, otherwise the first IPv6 address
+	Addresses  []string //all addresses of the neighbor in the order reported
+	Platform   string
+	Version    string
+	LocalPort  string
+	RemotePort string
+	NativeVLAN int //the native VLAN of the remote port advertised by the neighbor. 0 - unknown
+}
+
+// AddressFamily - the family of the management addresses preferred for the neighbors reporting several addresses
+type AddressFamily string
+
+const (
+	FamilyIPv4 AddressFamily = "ipv4"
+	FamilyIPv6 AddressFamily = "ipv6"
+)
+
+// ParseAddressFamily checks the name of the address family
+func ParseAddressFamily(name string) (AddressFamily, error) {
+	switch family := AddressFamily(strings.ToLower(name)); family {
+	case FamilyIPv4, FamilyIPv6:
+		return family, nil
+	}
+
+	return "", fmt.Errorf("address family [%s]: %w", name, ErrInvalidAddressFamily)
+}
+
+// SetAddresses sets the addresses of the neighbor. The addresses are normalized, the invalid and the link-local
+// addresses are skipped (the link-local addresses can not be reached without the zone).
+func (r *NeighborReport) SetAddresses(addresses ...string) {
+	r.Address = ""
+	r.Addresses = nil
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil || ip.IsLinkLocalUnicast() || containsString(r.Addresses, ip.String()) {
+			continue
+		}
+		r.Addresses = append(r.Addresses, ip.String())
+	}
+
+	r.Address = r.PreferredAddress(FamilyIPv4)
+}
+
+// PreferredAddress returns the first address of the family, otherwise the first address of the neighbor
+func (r NeighborReport) PreferredAddress(family AddressFamily) string {
+	if address := SelectAddress(r.Addresses, family); address != "" {
+		return address
+	}
+
+	return r.Address
+}
+
+// SelectAddress returns the first address of the family, otherwise the first address of the list
+func SelectAddress(addresses []string, family AddressFamily) string {
+	for _, address := range addresses {
+		if isIPv4 := net.ParseIP(address).To4() != nil; isIPv4 == (family == FamilyIPv4) {
+			return address
+		}
+	}
+	if len(addresses) > 0 {
+		return addresses[0]
+	}
+
+	return ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r DeviceReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("DeviceReport {Name: %s, Address: %s", r.Name, r.Address))
+	if r.Platform != "" || r.Version != "" {
+		sb.WriteString(fmt.Sprintf(", Platform: %s, Version: %s", r.Platform, r.Version))
+	}
+	if len(r.Neighbors) == 0 {
+		sb.WriteString("}")
+	} else {
+		sb.WriteString(fmt.Sprintf(", Neighbors: %s}", r.Neighbors))
+	}
+
+	return sb.String()
+}
+
+func (r NeighborReport) String() string {
+	return fmt.Sprintf("NeighborReport {Name: %s, Address: %s, Platform: %s, Version: %s, Ports: %s <-> %s}",
+		r.Name, r.Address, r.Platform, r.Version, r.LocalPort, r.RemotePort)
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestNeighborReport_SetAddresses(t *testing.T) {
+	tests := []struct {
+		name          string
+		addresses     []string
+		wantAddress   string
+		wantAddresses []string
+		wantIPv6      string
+	}{
+		{"ipv4", []string{"10.0.0.1"}, "10.0.0.1", []string{"10.0.0.1"}, "10.0.0.1"},
+		{"ipv6 first", []string{"2001:DB8::1", "10.0.0.1"}, "10.0.0.1", []string{"2001:db8::1", "10.0.0.1"}, "2001:db8::1"},
+		{"ipv6 only", []string{"2001:db8:0::1"}, "2001:db8::1", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"link-local", []string{"FE80::1", "2001:db8::1"}, "2001:db8::1", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"duplicates and garbage", []string{"10.0.0.1", "10.0.0.1", "00e0-fc00-0001", ""}, "10.0.0.1", []string{"10.0.0.1"}, "10.0.0.1"},
+		{"none", []string{"fe80::1"}, "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var neighbor domain.NeighborReport
+			neighbor.SetAddresses(tt.addresses...)
+
+			if neighbor.Address != tt.wantAddress || !reflect.DeepEqual(neighbor.Addresses, tt.wantAddresses) {
+				t.Errorf("SetAddresses() = %v %v, want %v %v", neighbor.Address, neighbor.Addresses, tt.wantAddress, tt.wantAddresses)
+			}
+			if got := neighbor.PreferredAddress(domain.FamilyIPv6); got != tt.wantIPv6 {
+				t.Errorf("PreferredAddress(ipv6) = %v, want %v", got, tt.wantIPv6)
+			}
+		})
+	}
+}
+
+func TestParseAddressFamily(t *testing.T) {
+	if family, err := domain.ParseAddressFamily("IPv6"); err != nil || family != domain.FamilyIPv6 {
+		t.Errorf("ParseAddressFamily(IPv6) = %v, %v, want %v", family, err, domain.FamilyIPv6)
+	}
+	if _, err := domain.ParseAddressFamily("ipx"); !errors.Is(err, domain.ErrInvalidAddressFamily) {
+		t.Errorf("ParseAddressFamily(ipx) error = %v, want %v", err, domain.ErrInvalidAddressFamily)
+	}
+}
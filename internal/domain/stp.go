@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// STPInstance - state of the spanning tree instance (VLAN of PVST or MST instance) on the switch
+type STPInstance struct {
+	Instance     string //e.g. VLAN0010 or MST0
+	RootPriority int
+	RootMAC      string //normalized by NormalizeMAC
+	BridgeMAC    string //the MAC address of the switch itself, normalized by NormalizeMAC
+	IsRoot       bool
+	RootPort     string //empty on the root bridge
+	BlockedPorts []string
+}
+
+// STPRoot - root bridge of the spanning tree instance as seen by the switches of the network
+type STPRoot struct {
+	Instance string
+	Priority int
+	MAC      string
+	Switch   Switch   //the root bridge. Filled if Found
+	Found    bool     //the root bridge is one of the switches of the network
+	SeenBy   []Switch //the switches that consider this bridge the root
+}
+
+// BlockedLink - link blocked by the spanning tree in some instances
+type BlockedLink struct {
+	Link      Link
+	Instances []string
+}
+
+func (s *Switch) SetSTP(instances []STPInstance) {
+	s.stp = instances
+}
+
+// STP returns the spanning tree instances of the switch, if they were collected
+func (s *Switch) STP() []STPInstance {
+	return s.stp
+}
+
+// STPRoots returns the root bridges of every spanning tree instance. Several roots of one instance mean
+// that the switches disagree, e.g. the instance is split into separate domains.
+func (n *Network) STPRoots() []STPRoot {
+	type rootKey struct {
+		instance string
+		priority int
+		mac      string
+	}
+
+	roots := make(map[rootKey]*STPRoot)
+	bridges := make(map[string]Switch) //instance + bridge MAC -> switch
+	for _, sw := range n.Switches() {
+		for _, instance := range sw.STP() {
+			bridges[instance.Instance+" "+instance.BridgeMAC] = sw
+
+			key := rootKey{instance.Instance, instance.RootPriority, instance.RootMAC}
+			root, ok := roots[key]
+			if !ok {
+				root = &STPRoot{Instance: instance.Instance, Priority: instance.RootPriority, MAC: instance.RootMAC}
+				roots[key] = root
+			}
+			root.SeenBy = append(root.SeenBy, sw)
+			if instance.IsRoot {
+				root.Switch, root.Found = sw, true
+			}
+		}
+	}
+
+	result := make([]STPRoot, 0, len(roots))
+	for _, root := range roots {
+		if sw, ok := bridges[root.Instance+" "+root.MAC]; ok && !root.Found {
+			root.Switch, root.Found = sw, true
+		}
+		result = append(result, *root)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Instance != result[j].Instance {
+			return lessInstance(result[i].Instance, result[j].Instance)
+		}
+		return len(result[i].SeenBy) > len(result[j].SeenBy)
+	})
+
+	return result
+}
+
+// BlockedLinks returns the links blocked by the spanning tree on either end
+func (n *Network) BlockedLinks() []BlockedLink {
+	var blocked []BlockedLink
+	for _, link := range n.Links() {
+		if instances := n.BlockedInstances(link); len(instances) > 0 {
+			blocked = append(blocked, BlockedLink{Link: link, Instances: instances})
+		}
+	}
+
+	return blocked
+}
+
+// BlockedInstances returns the spanning tree instances in which the link is blocked on either end.
+// The blocked port-channel blocks the links of all its member ports.
+func (n *Network) BlockedInstances(link Link) []string {
+	var instances []string
+	seen := make(map[string]bool)
+	for _, end := range []struct{ address, port string }{{link.from, link.fromPort}, {link.to, link.toPort}} {
+		sw := n.switches[end.address]
+		for _, instance := range sw.STP() {
+			if seen[instance.Instance] {
+				continue
+			}
+			for _, port := range instance.BlockedPorts {
+				if sw.SamePort(port, end.port) {
+					seen[instance.Instance] = true
+					instances = append(instances, instance.Instance)
+					break
+				}
+			}
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return lessInstance(instances[i], instances[j])
+	})
+
+	return instances
+}
+
+// lessInstance compares the names of the instances by the number, so that VLAN0002 goes before VLAN0010 and MST2 before MST10
+func lessInstance(a, b string) bool {
+	prefixA := strings.TrimRightFunc(a, unicode.IsDigit)
+	prefixB := strings.TrimRightFunc(b, unicode.IsDigit)
+	if prefixA != prefixB {
+		return prefixA < prefixB
+	}
+
+	numberA, _ := strconv.Atoi(a[len(prefixA):])
+	numberB, _ := strconv.Atoi(b[len(prefixB):])
+
+	return numberA < numberB
+}
@@ -0,0 +1,69 @@
+package domain_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestSTPRoots(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	//sw1 is the root of VLAN0001, the root of VLAN0010 is not crawled, sw5 is cut off and considers itself the root
+	mac := func(i int) string { return fmt.Sprintf("00:00:00:00:00:%02d", i) }
+	for i := 0; i < 5; i++ {
+		instances := []domain.STPInstance{
+			{Instance: "VLAN0001", RootPriority: 32769, RootMAC: mac(1), BridgeMAC: mac(i + 1), IsRoot: i == 0},
+			{Instance: "VLAN0010", RootPriority: 4106, RootMAC: mac(99), BridgeMAC: mac(i + 1)},
+		}
+		if i == 4 {
+			instances[1].RootMAC = mac(5)
+		}
+		if i == 3 {
+			instances[0].BlockedPorts = []string{"GigabitEthernet0/24"}
+		}
+		sw[i].SetSTP(instances)
+		network.UpdateSwitch(sw[i])
+	}
+
+	var roots []string
+	for _, root := range network.STPRoots() {
+		name := root.MAC
+		if root.Found {
+			name = root.Switch.Name()
+		}
+		roots = append(roots, fmt.Sprintf("%s %s %d", root.Instance, name, len(root.SeenBy)))
+	}
+	want := []string{"VLAN0001 sw1 5", "VLAN0010 00:00:00:00:00:99 4", "VLAN0010 sw5 1"}
+	if !reflect.DeepEqual(roots, want) {
+		t.Errorf("Network.STPRoots() = %v, want %v", roots, want)
+	}
+
+	blocked := network.BlockedLinks()
+	if len(blocked) != 1 || blocked[0].Link.FromAddress() != "192.168.1.3" || blocked[0].Link.ToAddress() != "192.168.1.4" ||
+		!reflect.DeepEqual(blocked[0].Instances, []string{"VLAN0001"}) {
+		t.Errorf("Network.BlockedLinks() = %v, want the link sw3-sw4 blocked in VLAN0001", blocked)
+	}
+
+	restored, _ := domain.NetworkFromJSON(network.ToJSON())
+	if !reflect.DeepEqual(restored.STPRoots(), network.STPRoots()) {
+		t.Errorf("Network.STPRoots() after NetworkFromJSON() = %v", restored.STPRoots())
+	}
+}
+
+func TestBlockedLinksPortChannel(t *testing.T) {
+	network, sw := newTestNetwork()
+
+	//the spanning tree of sw3 blocks the port-channel, CDP reports its member port of the link to sw5
+	sw[2].SetPortChannels([]domain.PortChannel{{Port: "Po1", Members: []string{"GigabitEthernet0/2", "GigabitEthernet0/3"}}})
+	sw[2].SetSTP([]domain.STPInstance{{Instance: "VLAN0010", RootPriority: 4106, RootMAC: "00:00:00:00:00:01", BlockedPorts: []string{"Po1"}}})
+	network.UpdateSwitch(sw[2])
+
+	blocked := network.BlockedLinks()
+	if len(blocked) != 1 || blocked[0].Link.FromAddress() != "192.168.1.3" || blocked[0].Link.ToAddress() != "192.168.1.5" ||
+		!reflect.DeepEqual(blocked[0].Instances, []string{"VLAN0010"}) {
+		t.Errorf("Network.BlockedLinks() = %v, want the link sw3-sw5 blocked in VLAN0010", blocked)
+	}
+}
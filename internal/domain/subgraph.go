@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/vps2/cisco-switches-crawler/pkg/queue"
+)
+
+// Neighborhood returns the part of the network that consists of the switches no further than hops from the center
+func (n *Network) Neighborhood(center Switch, hops int) (*Network, error) {
+	if _, ok := n.graph[center.Address()]; !ok {
+		return nil, fmt.Errorf("network neighborhood [%s]: %w", center.Address(), ErrSwitchNotInNetwork)
+	}
+
+	distance := map[string]int{center.Address(): 0}
+	q := queue.New[string]()
+	q.Push(center.Address())
+	for !q.IsEmpty() {
+		curr := q.Pop()
+		if distance[curr] == hops {
+			continue
+		}
+
+		for _, neighbor := range n.sortedNeighbors(curr) {
+			if _, ok := distance[neighbor]; !ok {
+				distance[neighbor] = distance[curr] + 1
+				q.Push(neighbor)
+			}
+		}
+	}
+
+	subnetwork := NewNetwork()
+	for address := range distance {
+		subnetwork.AddSwitch(n.switches[address])
+	}
+	for key, links := range n.links {
+		_, aFound := distance[key.a]
+		_, bFound := distance[key.b]
+		if aFound && bFound {
+			subnetwork.graph[key.a].Add(key.b)
+			subnetwork.graph[key.b].Add(key.a)
+			subnetwork.links[key] = append([]Link(nil), links...)
+		}
+	}
+
+	return subnetwork, nil
+}
@@ -3,12 +3,37 @@ package domain
 import (
 	"fmt"
 	"net"
+	"strings"
+)
+
+// Status - result of the crawl of the switch
+type Status string
+
+const (
+	StatusNotCrawled Status = "not_crawled" //the switch is known from the neighbors, but has not been polled yet
+	StatusCrawled    Status = "crawled"
+	StatusFailed     Status = "failed"    //failed to connect to the switch or to get information from it
+	StatusDiscarded  Status = "discarded" //the switch has been discarded by the filter
 )
 
 // Switch provides information about the switch
 type Switch struct {
-	name    string
-	address string
+	name     string
+	address  string
+	status   Status
+	platform string
+	version  string
+	serial   string
+	dnsName  string //the name of the address in DNS (PTR record)
+	attempts int    //the number of the connections to the switch, including the retries
+	failure  string //the error of the last failed connection or poll
+	depth    int    //the number of hops from the switch from which the crawl started
+	macTable []MACEntry
+	arpTable []ARPEntry
+	vlans    []VLAN
+	stp      []STPInstance
+
+	portChannels []PortChannel
 }
 
 func NewSwitch(address string) (*Switch, error) {
@@ -18,6 +43,7 @@ func NewSwitch(address string) (*Switch, error) {
 
 	return &Switch{
 		address: address,
+		status:  StatusNotCrawled,
 	}, nil
 }
 
@@ -33,6 +59,112 @@ func (s *Switch) Address() string {
 	return s.address
 }
 
+func (s *Switch) SetStatus(status Status) {
+	s.status = status
+}
+
+func (s *Switch) Status() Status {
+	return s.status
+}
+
+func (s *Switch) SetPlatform(platform string) {
+	s.platform = platform
+}
+
+func (s *Switch) Platform() string {
+	return s.platform
+}
+
+func (s *Switch) SetVersion(version string) {
+	s.version = version
+}
+
+func (s *Switch) Version() string {
+	return s.version
+}
+
+func (s *Switch) SetSerial(serial string) {
+	s.serial = serial
+}
+
+func (s *Switch) Serial() string {
+	return s.serial
+}
+
+func (s *Switch) SetDNSName(name string) {
+	s.dnsName = strings.TrimSuffix(name, ".")
+}
+
+func (s *Switch) DNSName() string {
+	return s.dnsName
+}
+
+// DNSMismatch reports whether the name of the switch differs from its name in DNS. The host parts of the names
+// are compared ignoring the case, e.g. "SW1" and "sw1.corp.local" match.
+func (s *Switch) DNSMismatch() bool {
+	if s.name == "" || s.dnsName == "" {
+		return false
+	}
+
+	return !strings.EqualFold(hostPart(s.name), hostPart(s.dnsName))
+}
+
+func hostPart(name string) string {
+	return strings.SplitN(name, ".", 2)[0]
+}
+
+func (s *Switch) SetAttempts(attempts int) {
+	s.attempts = attempts
+}
+
+func (s *Switch) Attempts() int {
+	return s.attempts
+}
+
+// SetFailure sets the error that failed the crawl of the switch, empty if the crawl succeeded
+func (s *Switch) SetFailure(failure string) {
+	s.failure = failure
+}
+
+func (s *Switch) Failure() string {
+	return s.failure
+}
+
+func (s *Switch) SetDepth(depth int) {
+	s.depth = depth
+}
+
+func (s *Switch) Depth() int {
+	return s.depth
+}
+
+func (s *Switch) SetMACTable(entries []MACEntry) {
+	s.macTable = entries
+}
+
+// MACTable returns the MAC addresses learned by the switch, if they were collected
+func (s *Switch) MACTable() []MACEntry {
+	return s.macTable
+}
+
+func (s *Switch) SetARPTable(entries []ARPEntry) {
+	s.arpTable = entries
+}
+
+// ARPTable returns the ip addresses resolved by the switch, if they were collected
+func (s *Switch) ARPTable() []ARPEntry {
+	return s.arpTable
+}
+
+func (s *Switch) SetVLANs(vlans []VLAN) {
+	s.vlans = vlans
+}
+
+// VLANs returns the VLAN database of the switch, if it was collected
+func (s *Switch) VLANs() []VLAN {
+	return s.vlans
+}
+
 func (s *Switch) String() string {
 	return fmt.Sprintf("Switch {Name: %s, Address: %s}", s.name, s.address)
 }
@@ -0,0 +1,32 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestSwitch_DNSMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		dnsName string
+		want    bool
+	}{
+		{"sw1", "sw1.corp.local.", false},
+		{"SW1.corp.local", "sw1.corp.local", false},
+		{"sw1", "sw1-old.corp.local", true},
+		{"", "sw1.corp.local", false},
+		{"sw1", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.dnsName, func(t *testing.T) {
+			sw, _ := domain.NewSwitch("10.0.0.1")
+			sw.SetName(tt.name)
+			sw.SetDNSName(tt.dnsName)
+			if got := sw.DNSMismatch(); got != tt.want {
+				t.Errorf("Switch.DNSMismatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
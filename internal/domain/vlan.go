@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	minVLAN = 1
+	maxVLAN = 4094
+)
+
+// VLAN - entry of the VLAN database of the switch
+type VLAN struct {
+	ID     int
+	Name   string
+	Status string
+}
+
+// VLANRange - VLANs from From to To inclusive
+type VLANRange struct {
+	From int
+	To   int
+}
+
+// VLANSet - sorted non-overlapping ranges of VLANs. A nil set means that the VLANs are unknown,
+// an empty set - that there are no VLANs.
+type VLANSet []VLANRange
+
+// PortVLANs - VLANs of the port on one end of the link
+type PortVLANs struct {
+	Native  int     //the native VLAN of the trunk or the VLAN of the access port. 0 - unknown
+	Allowed VLANSet //the VLANs allowed on the trunk. nil - the port is not a trunk or the trunks were not collected
+	Active  VLANSet //the allowed VLANs that exist on the switch
+}
+
+// TrunkPort - trunk port of the switch
+type TrunkPort struct {
+	Port  string
+	VLANs PortVLANs
+}
+
+// MismatchKind - kind of the difference between the VLANs of the ends of the link
+type MismatchKind string
+
+const (
+	MismatchNative  MismatchKind = "native"
+	MismatchAllowed MismatchKind = "allowed"
+)
+
+// VLANMismatch - link whose ends disagree about the VLANs
+type VLANMismatch struct {
+	Link     Link
+	Kind     MismatchKind
+	FromOnly VLANSet //the VLANs allowed only on the first end. Filled for MismatchAllowed
+	ToOnly   VLANSet //the VLANs allowed only on the second end. Filled for MismatchAllowed
+}
+
+// ParseVLANSet parses the list of VLANs in the Cisco notation, e.g. "1,10,20-30". "none" is the empty set.
+func ParseVLANSet(list string) (VLANSet, error) {
+	set := VLANSet{}
+
+	list = strings.TrimSpace(list)
+	if list == "" || strings.EqualFold(list, "none") {
+		return set, nil
+	}
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		bounds := strings.SplitN(item, "-", 2)
+		from, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse vlans [%s]: %w", list, ErrInvalidVLAN)
+		}
+		to := from
+		if len(bounds) == 2 {
+			if to, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("parse vlans [%s]: %w", list, ErrInvalidVLAN)
+			}
+		}
+		if from < minVLAN || to > maxVLAN || from > to {
+			return nil, fmt.Errorf("parse vlans [%s]: %w", list, ErrInvalidVLAN)
+		}
+		set = append(set, VLANRange{From: from, To: to})
+	}
+
+	return set.normalize(), nil
+}
+
+// Contains reports whether the VLAN is in the set
+func (s VLANSet) Contains(vlan int) bool {
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].To >= vlan
+	})
+
+	return i < len(s) && s[i].From <= vlan
+}
+
+// Difference returns the VLANs of the set that are not in the other set
+func (s VLANSet) Difference(other VLANSet) VLANSet {
+	difference := VLANSet{}
+	for _, r := range s {
+		from := r.From
+		for _, o := range other {
+			if o.To < from || o.From > r.To {
+				continue
+			}
+			if o.From > from {
+				difference = append(difference, VLANRange{From: from, To: o.From - 1})
+			}
+			from = o.To + 1
+		}
+		if from <= r.To {
+			difference = append(difference, VLANRange{From: from, To: r.To})
+		}
+	}
+
+	return difference
+}
+
+// String returns the set in the Cisco notation
+func (s VLANSet) String() string {
+	if len(s) == 0 {
+		return "none"
+	}
+
+	items := make([]string, 0, len(s))
+	for _, r := range s {
+		if r.From == r.To {
+			items = append(items, strconv.Itoa(r.From))
+		} else {
+			items = append(items, fmt.Sprintf("%d-%d", r.From, r.To))
+		}
+	}
+
+	return strings.Join(items, ",")
+}
+
+// normalize sorts the ranges and joins the overlapping and adjacent ones
+func (s VLANSet) normalize() VLANSet {
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].From < s[j].From
+	})
+
+	normalized := VLANSet{}
+	for _, r := range s {
+		if last := len(normalized) - 1; last >= 0 && r.From <= normalized[last].To+1 {
+			if r.To > normalized[last].To {
+				normalized[last].To = r.To
+			}
+			continue
+		}
+		normalized = append(normalized, r)
+	}
+
+	return normalized
+}
+
+// carries reports whether the port passes the VLAN. The second result is false if it is unknown.
+func (p PortVLANs) carries(vlan int) (bool, bool) {
+	switch {
+	case p.Active != nil:
+		return p.Active.Contains(vlan), true
+	case p.Allowed != nil:
+		return p.Allowed.Contains(vlan), true
+	case p.Native != 0:
+		return p.Native == vlan, true
+	}
+
+	return false, false
+}
+
+// merge fills the unknown VLANs of the port with the VLANs of other
+func (p *PortVLANs) merge(other PortVLANs) {
+	if p.Native == 0 {
+		p.Native = other.Native
+	}
+	if p.Allowed == nil {
+		p.Allowed = other.Allowed
+	}
+	if p.Active == nil {
+		p.Active = other.Active
+	}
+}
+
+// VLANTopology returns the part of the network that carries the VLAN: the links passing the VLAN on both ends
+// (or on the only known end) and the switches having the VLAN in their database
+func (n *Network) VLANTopology(vlan int) *Network {
+	subnetwork := NewNetwork()
+	for _, sw := range n.Switches() {
+		for _, v := range sw.VLANs() {
+			if v.ID == vlan {
+				subnetwork.AddSwitch(sw)
+				break
+			}
+		}
+	}
+
+	for key, links := range n.links {
+		for _, link := range links {
+			fromCarries, fromKnown := link.fromVLANs.carries(vlan)
+			toCarries, toKnown := link.toVLANs.carries(vlan)
+			if (fromKnown && !fromCarries) || (toKnown && !toCarries) || (!fromKnown && !toKnown) {
+				continue
+			}
+
+			subnetwork.AddSwitch(n.switches[key.a])
+			subnetwork.AddSwitch(n.switches[key.b])
+			subnetwork.graph[key.a].Add(key.b)
+			subnetwork.graph[key.b].Add(key.a)
+			subnetwork.links[key] = append(subnetwork.links[key], link)
+		}
+	}
+
+	return subnetwork
+}
+
+// VLANMismatches returns the links whose ends have different native VLANs or different allowed VLANs.
+// Only the VLANs known on both ends are compared.
+func (n *Network) VLANMismatches() []VLANMismatch {
+	var mismatches []VLANMismatch
+	for _, link := range n.Links() {
+		from, to := link.fromVLANs, link.toVLANs
+		if from.Native != 0 && to.Native != 0 && from.Native != to.Native {
+			mismatches = append(mismatches, VLANMismatch{Link: link, Kind: MismatchNative})
+		}
+		if from.Allowed != nil && to.Allowed != nil {
+			fromOnly, toOnly := from.Allowed.Difference(to.Allowed), to.Allowed.Difference(from.Allowed)
+			if len(fromOnly) > 0 || len(toOnly) > 0 {
+				mismatches = append(mismatches, VLANMismatch{Link: link, Kind: MismatchAllowed, FromOnly: fromOnly, ToOnly: toOnly})
+			}
+		}
+	}
+
+	return mismatches
+}
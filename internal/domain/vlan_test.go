@@ -0,0 +1,140 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+func TestParseVLANSet(t *testing.T) {
+	tests := []struct {
+		list    string
+		expect  string
+		wantErr error
+	}{
+		{"1-4094", "1-4094", nil},
+		{"30,10,20-25,21,26", "10,20-26,30", nil},
+		{"none", "none", nil},
+		{"", "none", nil},
+		{"10-5", "", domain.ErrInvalidVLAN},
+		{"0,4095", "", domain.ErrInvalidVLAN},
+		{"ten", "", domain.ErrInvalidVLAN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			set, err := domain.ParseVLANSet(tt.list)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseVLANSet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && set.String() != tt.expect {
+				t.Errorf("ParseVLANSet() = %s, want %s", set, tt.expect)
+			}
+		})
+	}
+}
+
+func TestVLANSet_Difference(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect string
+	}{
+		{"1-4094", "1-4094", "none"},
+		{"1-100", "10-20,50", "1-9,21-49,51-100"},
+		{"10,20", "1-4094", "none"},
+		{"10,20", "none", "10,20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" - "+tt.b, func(t *testing.T) {
+			a, _ := domain.ParseVLANSet(tt.a)
+			b, _ := domain.ParseVLANSet(tt.b)
+			if got := a.Difference(b).String(); got != tt.expect {
+				t.Errorf("VLANSet.Difference() = %s, want %s", got, tt.expect)
+			}
+		})
+	}
+}
+
+// newVLANNetwork creates the trunks sw1-sw2-sw3, where sw3 allows only VLANs 1 and 10,
+// and the access link sw1-sw4 with different VLANs on its ends
+func newVLANNetwork() *domain.Network {
+	trunk := func(allowed string) domain.PortVLANs {
+		set, _ := domain.ParseVLANSet(allowed)
+		return domain.PortVLANs{Native: 1, Allowed: set}
+	}
+
+	_, sw := newTestNetwork()
+	sw[2].SetVLANs([]domain.VLAN{{ID: 1, Name: "default"}, {ID: 10, Name: "USERS"}, {ID: 30, Name: "PRINTERS"}})
+
+	network := domain.NewNetwork()
+	for _, s := range sw[:4] {
+		network.AddSwitch(s)
+	}
+	network.AddLink(sw[0], sw[1], domain.WithPorts("Gi0/1", "Gi0/24"), domain.WithVLANs(trunk("1-4094"), trunk("1-4094")))
+	network.AddLink(sw[1], sw[2], domain.WithPorts("Gi0/1", "Gi0/24"), domain.WithVLANs(trunk("1-4094"), trunk("1,10")))
+	//each switch of the access link knows the VLAN of the other end from CDP
+	network.AddLink(sw[0], sw[3], domain.WithPorts("Fa0/5", "Fa0/1"), domain.WithVLANs(domain.PortVLANs{}, domain.PortVLANs{Native: 1}))
+	network.AddLink(sw[3], sw[0], domain.WithPorts("Fa0/1", "Fa0/5"), domain.WithVLANs(domain.PortVLANs{}, domain.PortVLANs{Native: 30}))
+
+	return network
+}
+
+func TestVLANTopology(t *testing.T) {
+	network := newVLANNetwork()
+
+	tests := []struct {
+		vlan     int
+		switches []string
+		links    int
+	}{
+		{1, []string{"sw1", "sw2", "sw3"}, 2},
+		{10, []string{"sw1", "sw2", "sw3"}, 2},
+		{20, []string{"sw1", "sw2"}, 1},
+		{30, []string{"sw1", "sw2", "sw3"}, 1}, //sw3 has the VLAN in the database, but it is not allowed on its trunk
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.vlan), func(t *testing.T) {
+			topology := network.VLANTopology(tt.vlan)
+
+			var names []string
+			for _, sw := range topology.Switches() {
+				names = append(names, sw.Name())
+			}
+			if !reflect.DeepEqual(names, tt.switches) || len(topology.Links()) != tt.links {
+				t.Errorf("Network.VLANTopology() = %v with %d links, want %v with %d links", names, len(topology.Links()), tt.switches, tt.links)
+			}
+		})
+	}
+}
+
+func TestVLANMismatches(t *testing.T) {
+	network := newVLANNetwork()
+
+	var got []string
+	for _, mismatch := range network.VLANMismatches() {
+		got = append(got, fmt.Sprintf("%s-%s %s %s/%s", mismatch.Link.FromPort(), mismatch.Link.ToPort(), mismatch.Kind,
+			mismatch.FromOnly, mismatch.ToOnly))
+	}
+
+	want := []string{
+		"Fa0/5-Fa0/1 native none/none",
+		"Gi0/1-Gi0/24 allowed 2-9,11-4094/none",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Network.VLANMismatches() = %v, want %v", got, want)
+	}
+
+	restored, err := domain.NetworkFromJSON(network.ToJSON())
+	if err != nil {
+		t.Fatalf("NetworkFromJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(restored.VLANMismatches(), network.VLANMismatches()) {
+		t.Errorf("Network.VLANMismatches() after NetworkFromJSON() = %v", restored.VLANMismatches())
+	}
+}
@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+)
+
+const configExt = ".cfg"
+
+var notFileNameRe = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+
+// Dir stores the configurations of the switches as DIR/<hostname>.cfg files
+type Dir struct {
+	path string
+}
+
+func NewDir(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, fmt.Errorf("backup dir [%s]: %w", path, err)
+	}
+
+	return &Dir{
+		path: path,
+	}, nil
+}
+
+func (d *Dir) Save(hostname string, config string) error {
+	fileName := notFileNameRe.ReplaceAllString(hostname, "_")
+	if fileName == "" || fileName == "." || fileName == ".." {
+		return fmt.Errorf("backup save [%s]: wrong host name", hostname)
+	}
+
+	if err := os.WriteFile(filepath.Join(d.path, fileName+configExt), []byte(config), 0o644); err != nil {
+		return fmt.Errorf("backup save [%s]: %w", hostname, err)
+	}
+
+	return nil
+}
+
+// Commit commits the changes of the configurations to the git repository in the directory.
+// The repository is created if necessary. Nothing is committed if the configurations have not changed.
+func (d *Dir) Commit(message string) error {
+	if _, err := os.Stat(filepath.Join(d.path, ".git")); errors.Is(err, os.ErrNotExist) {
+		if _, err := d.git("init", "-q"); err != nil {
+			return fmt.Errorf("backup commit: %w", err)
+		}
+	}
+
+	if _, err := d.git("add", "-A"); err != nil {
+		return fmt.Errorf("backup commit: %w", err)
+	}
+	if _, err := d.git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+
+	args := []string{"commit", "-q", "-m", message}
+	if email, _ := d.git("config", "user.email"); len(bytes.TrimSpace(email)) == 0 {
+		args = append([]string{"-c", "user.name=cisco_crawler", "-c", "user.email=cisco_crawler@localhost"}, args...)
+	}
+	if _, err := d.git(args...); err != nil {
+		return fmt.Errorf("backup commit: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Dir) git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", d.path}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return output, fmt.Errorf("git %s: %w: %s", args[0], err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return output, nil
+}
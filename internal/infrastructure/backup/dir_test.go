@@ -0,0 +1,78 @@
+package backup_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/backup"
+)
+
+func TestDir_Save(t *testing.T) {
+	path := t.TempDir()
+	dir, _ := backup.NewDir(path)
+
+	tests := []struct {
+		hostname string
+		fileName string
+		wantErr  bool
+	}{
+		{"SW1", "SW1.cfg", false},
+		{"core-1.corp.local", "core-1.corp.local.cfg", false},
+		{"../sw 2", ".._sw_2.cfg", false},
+		{"..", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			err := dir.Save(tt.hostname, "hostname "+tt.hostname+"\n")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Dir.Save() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if data, err := os.ReadFile(filepath.Join(path, tt.fileName)); err != nil || string(data) != "hostname "+tt.hostname+"\n" {
+				t.Errorf("Dir.Save() wrote %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestDir_Commit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	path := t.TempDir()
+	dir, _ := backup.NewDir(path)
+
+	commits := func() int {
+		out, err := exec.Command("git", "-C", path, "rev-list", "--count", "HEAD").Output()
+		if err != nil {
+			t.Fatalf("git rev-list: %v", err)
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(string(out)))
+		return count
+	}
+
+	dir.Save("SW1", "hostname SW1\n")
+	if err := dir.Commit("first"); err != nil {
+		t.Fatalf("Dir.Commit() error = %v", err)
+	}
+	if err := dir.Commit("nothing changed"); err != nil {
+		t.Fatalf("Dir.Commit() error = %v", err)
+	}
+	if got := commits(); got != 1 {
+		t.Errorf("Dir.Commit() made %d commits, want %d", got, 1)
+	}
+
+	dir.Save("SW1", "hostname SW1\nntp server 10.0.0.1\n")
+	dir.Commit("second")
+	if got := commits(); got != 2 {
+		t.Errorf("Dir.Commit() made %d commits, want %d", got, 2)
+	}
+}
@@ -0,0 +1,82 @@
+package capture_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/capture"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+var result = usecase.DeviceCommands{
+	Name:    "SW1",
+	Address: "192.168.1.1",
+	Results: []usecase.CommandResult{
+		{Command: "show vlan brief", Output: "1    default    active\n"},
+		{Command: "show inventory", Error: "the command was rejected by the switch"},
+	},
+}
+
+func TestDir_Save(t *testing.T) {
+	path := t.TempDir()
+	dir, _ := capture.NewDir(path)
+
+	if err := dir.Save(result); err != nil {
+		t.Fatalf("Dir.Save() error = %v", err)
+	}
+
+	files := map[string]string{
+		"show_vlan_brief.txt": "1    default    active\n",
+		"errors.txt":          "show inventory: the command was rejected by the switch\n",
+	}
+	for name, want := range files {
+		if data, err := os.ReadFile(filepath.Join(path, "SW1", name)); err != nil || string(data) != want {
+			t.Errorf("Dir.Save() wrote %s = %q, %v, want %q", name, data, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(path, "SW1", "show_inventory.txt")); !os.IsNotExist(err) {
+		t.Errorf("Dir.Save() wrote the output of the rejected command")
+	}
+}
+
+func TestJSON_WriteTo(t *testing.T) {
+	store := capture.NewJSON()
+	store.Save(result)
+	store.Save(usecase.DeviceCommands{Name: "SW2", Address: "192.168.1.2", Error: "authentication error"})
+
+	var b bytes.Buffer
+	if _, err := store.WriteTo(&b); err != nil {
+		t.Fatalf("JSON.WriteTo() error = %v", err)
+	}
+
+	want := `{
+   "devices": [
+      {
+         "name": "SW1",
+         "address": "192.168.1.1",
+         "commands": [
+            {
+               "command": "show vlan brief",
+               "output": "1    default    active\n"
+            },
+            {
+               "command": "show inventory",
+               "output": "",
+               "error": "the command was rejected by the switch"
+            }
+         ]
+      },
+      {
+         "name": "SW2",
+         "address": "192.168.1.2",
+         "error": "authentication error"
+      }
+   ]
+}
+`
+	if b.String() != want {
+		t.Errorf("JSON.WriteTo() = %s, want %s", b.String(), want)
+	}
+}
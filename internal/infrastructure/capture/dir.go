@@ -0,0 +1,82 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+const errorsFileName = "errors.txt"
+
+var notFileNameRe = regexp.MustCompile(`[^A-Za-z0-9_.\-]+`)
+
+// Dir stores the output of every command as DIR/<hostname>/<command>.txt.
+// The errors of the device are collected in DIR/<hostname>/errors.txt.
+type Dir struct {
+	path string
+}
+
+func NewDir(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, fmt.Errorf("capture dir [%s]: %w", path, err)
+	}
+
+	return &Dir{
+		path: path,
+	}, nil
+}
+
+func (d *Dir) Save(result usecase.DeviceCommands) error {
+	name := result.Name
+	if name == "" {
+		name = result.Address
+	}
+
+	deviceDir := filepath.Join(d.path, fileName(name))
+	if err := os.MkdirAll(deviceDir, 0o755); err != nil {
+		return fmt.Errorf("capture save [%s]: %w", name, err)
+	}
+
+	var errors strings.Builder
+	if result.Error != "" {
+		errors.WriteString(result.Error + "\n")
+	}
+	for _, commandResult := range result.Results {
+		if commandResult.Error != "" {
+			errors.WriteString(fmt.Sprintf("%s: %s\n", commandResult.Command, commandResult.Error))
+		}
+		if commandResult.Output == "" {
+			continue
+		}
+
+		outputPath := filepath.Join(deviceDir, fileName(commandResult.Command)+".txt")
+		if err := os.WriteFile(outputPath, []byte(commandResult.Output), 0o644); err != nil {
+			return fmt.Errorf("capture save [%s]: %w", name, err)
+		}
+	}
+
+	errorsPath := filepath.Join(deviceDir, errorsFileName)
+	if errors.Len() == 0 {
+		os.Remove(errorsPath) //the errors of the previous run are not relevant anymore
+		return nil
+	}
+	if err := os.WriteFile(errorsPath, []byte(errors.String()), 0o644); err != nil {
+		return fmt.Errorf("capture save [%s]: %w", name, err)
+	}
+
+	return nil
+}
+
+// fileName makes the file name from the host name or the command, e.g. "show_vlan_brief"
+func fileName(s string) string {
+	name := strings.Trim(notFileNameRe.ReplaceAllString(s, "_"), "_")
+	if name == "" || strings.Trim(name, ".") == "" {
+		return "_"
+	}
+
+	return name
+}
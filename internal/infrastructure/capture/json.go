@@ -0,0 +1,65 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+type deviceJSON struct {
+	Name     string        `json:"name"`
+	Address  string        `json:"address"`
+	Error    string        `json:"error,omitempty"`
+	Attempts int           `json:"attempts,omitempty"`
+	Commands []commandJSON `json:"commands,omitempty"`
+}
+
+type commandJSON struct {
+	Command string `json:"command"`
+	Output  string `json:"output"`
+	Error   string `json:"error,omitempty"`
+}
+
+// JSON collects the outputs of the commands of all devices into one JSON document
+type JSON struct {
+	devices []deviceJSON
+}
+
+func NewJSON() *JSON {
+	return &JSON{
+		devices: []deviceJSON{},
+	}
+}
+
+func (j *JSON) Save(result usecase.DeviceCommands) error {
+	device := deviceJSON{Name: result.Name, Address: result.Address, Error: result.Error, Attempts: result.Attempts}
+	for _, commandResult := range result.Results {
+		device.Commands = append(device.Commands, commandJSON{
+			Command: commandResult.Command,
+			Output:  commandResult.Output,
+			Error:   commandResult.Error,
+		})
+	}
+	j.devices = append(j.devices, device)
+
+	return nil
+}
+
+// WriteTo writes the document with the outputs of all devices saved so far
+func (j *JSON) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(struct {
+		Devices []deviceJSON `json:"devices"`
+	}{j.devices}, "", "   ")
+	if err != nil {
+		return 0, fmt.Errorf("capture json: %w", err)
+	}
+
+	n, err := w.Write(append(data, '\n'))
+	if err != nil {
+		return int64(n), fmt.Errorf("capture json: %w", err)
+	}
+
+	return int64(n), nil
+}
@@ -0,0 +1,89 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+const version = 1
+
+var ErrUnknownVersion = errors.New("unknown version of the checkpoint")
+
+type checkpointJSON struct {
+	Version int             `json:"version"`
+	Queue   []string        `json:"queue"`
+	Visited []string        `json:"visited"`
+	Network json.RawMessage `json:"network"`
+}
+
+// File stores the checkpoint of the crawl in the JSON file. The file is replaced atomically,
+// so the crawl interrupted while saving keeps the previous checkpoint.
+type File struct {
+	path string
+}
+
+func NewFile(path string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+func (f *File) Save(checkpoint usecase.Checkpoint) error {
+	data, err := json.Marshal(checkpointJSON{
+		Version: version,
+		Queue:   checkpoint.Queue,
+		Visited: checkpoint.Visited,
+		Network: checkpoint.Network.ToJSON(),
+	})
+	if err != nil {
+		return fmt.Errorf("checkpoint save [%s]: %w", f.path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("checkpoint save [%s]: %w", f.path, err)
+	}
+	defer os.Remove(tmp.Name()) //nothing to remove after the rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("checkpoint save [%s]: %w", f.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("checkpoint save [%s]: %w", f.path, err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("checkpoint save [%s]: %w", f.path, err)
+	}
+
+	return nil
+}
+
+// Load reads the checkpoint saved to the file
+func (f *File) Load() (usecase.Checkpoint, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return usecase.Checkpoint{}, fmt.Errorf("checkpoint load [%s]: %w", f.path, err)
+	}
+
+	var item checkpointJSON
+	if err := json.Unmarshal(data, &item); err != nil {
+		return usecase.Checkpoint{}, fmt.Errorf("checkpoint load [%s]: %w", f.path, err)
+	}
+	if item.Version != version {
+		return usecase.Checkpoint{}, fmt.Errorf("checkpoint load [%s]: %w %d", f.path, ErrUnknownVersion, item.Version)
+	}
+
+	network, err := domain.NetworkFromJSON(item.Network)
+	if err != nil {
+		return usecase.Checkpoint{}, fmt.Errorf("checkpoint load [%s]: %w", f.path, err)
+	}
+
+	return usecase.Checkpoint{Network: network, Queue: item.Queue, Visited: item.Visited}, nil
+}
@@ -0,0 +1,76 @@
+package checkpoint_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/checkpoint"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+func TestFile_SaveLoad(t *testing.T) {
+	network := domain.NewNetwork()
+	sw1, _ := domain.NewSwitch("10.0.0.1")
+	sw1.SetName("SW1")
+	sw1.SetStatus(domain.StatusCrawled)
+	sw2, _ := domain.NewSwitch("10.0.0.2")
+	sw2.SetStatus(domain.StatusFailed)
+	sw2.SetAttempts(3)
+	sw2.SetFailure("client connect [10.0.0.2]: all vty lines are busy")
+	sw3, _ := domain.NewSwitch("10.0.0.3")
+	network.AddSwitch(*sw1)
+	network.AddSwitch(*sw2)
+	network.AddSwitch(*sw3)
+	network.AddLink(*sw1, *sw2, domain.WithPorts("Gi0/1", "Gi0/24"))
+	network.AddLink(*sw1, *sw3, domain.WithPorts("Gi0/2", "Gi0/24"))
+
+	file := checkpoint.NewFile(filepath.Join(t.TempDir(), "crawl.checkpoint"))
+	want := usecase.Checkpoint{Network: network, Queue: []string{"10.0.0.3"}, Visited: []string{"10.0.0.1", "10.0.0.2"}}
+	if err := file.Save(want); err != nil {
+		t.Fatalf("File.Save() error = %v", err)
+	}
+	want.Visited = want.Visited[:1]
+	if err := file.Save(want); err != nil { //replaces the previous checkpoint
+		t.Fatalf("File.Save() error = %v", err)
+	}
+
+	got, err := file.Load()
+	if err != nil {
+		t.Fatalf("File.Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Queue, want.Queue) || !reflect.DeepEqual(got.Visited, want.Visited) {
+		t.Errorf("File.Load() = queue %v, visited %v, want queue %v, visited %v", got.Queue, got.Visited, want.Queue, want.Visited)
+	}
+	if string(got.Network.ToJSON()) != string(network.ToJSON()) {
+		t.Errorf("File.Load() network = %s, want %s", got.Network.ToJSON(), network.ToJSON())
+	}
+}
+
+func TestFile_Load(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+	}{
+		{"unknown version", `{"version": 2, "network": {"network": []}}`, checkpoint.ErrUnknownVersion},
+		{"no file", "", os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "crawl.checkpoint")
+			if tt.text != "" {
+				if err := os.WriteFile(filename, []byte(tt.text), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := checkpoint.NewFile(filename).Load(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("File.Load() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,38 +1,65 @@
 package cisco
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/pkg/expect"
 )
 
 const (
 	newLine = "\n"
 	space   = " "
 
-	//different switches have different user name request labels
-	txtUsername = "Username:"
-	txtLogin    = "Login:"
-
-	txtPassword = "Password:"
-
-	txtAuthenticationFailed = "Authentication failed"
-	txtLoginInvalid         = "Login invalid"
-	txtBadPasswords         = "Bad passwords"
-	txtTimeoutExpired       = "timeout expired"
-	txtPrompt               = ">"
-	txtMore                 = "--More--"
-	txtDeviceSeparator      = "-------------------------"
+	txtTimeoutExpired   = "timeout expired"
+	txtPrompt           = ">"
+	txtPrivilegedPrompt = "#"
+	txtMore             = "--More--"
+	txtDeviceSeparator  = "-------------------------"
 
 	cmdShowNeighbors = "sh cdp nei det"
 )
 
-const defaultTelnetPort = 23
+const defaultPort = 23
+
+const defaultTimeout = time.Minute
+
+var ErrCommandRejected = errors.New("the command was rejected by the switch")
+
+// errTimeoutExpired - the switch closed the login, because the credentials were not entered in time
+var errTimeoutExpired = fmt.Errorf("the switch closed the login: %w", expect.ErrTimeout)
+
+// errClosedAfterPassword - the switch closed the connection after the password instead of the prompt,
+// e.g. the AAA server rejected the user without a message. The retries could lock the account.
+var errClosedAfterPassword = fmt.Errorf("the switch closed the connection after the password: %w", domain.ErrAuthentication)
+
+// rejectionPrefixes - the beginnings of the messages about the rejected commands: IOS and Eltex, Huawei, Aruba
+var rejectionPrefixes = []string{"%", "Error:", "Invalid input"}
+
+var promptRe = regexp.MustCompile(`^[A-Za-z0-9_.\-:/()@]+[>#]$`)
+var catOSPromptRe = regexp.MustCompile(`^[A-Za-z0-9_.\-]+> $`) //CatOS is crawled in the user mode
+
+// pagingEraseRe - the remains of the paging prompt erased by the switch with backspaces
+var pagingEraseRe = regexp.MustCompile(" *\x08+ *\x08*")
+
+// escapeSequenceRe - the control sequences of ANSI terminals moving the cursor and erasing the line
+var escapeSequenceRe = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+var portsRe = regexp.MustCompile(`(?m)Interface: (.*?),\s+Port ID \(outgoing port\): (.*?)\s*$`)
+var shortVersionRe = regexp.MustCompile(`Version:? (?:NmpSW: |McpSW: )?([^,\s]+)`)
 
-var re = regexp.MustCompile(`Device ID: (.*?)\r\n.*?\r\n.*?IP address: (.*?)\r\n`)
+// serialRe - the serial number of the chassis in the output of "show version": IOS and IOS-XE switches, NX-OS, CatOS
+var serialRe = regexp.MustCompile(`(?m)(?:System [Ss]erial [Nn]umber\s*:|Processor [Bb]oard ID|Serial #:)\s*([A-Za-z0-9\-]+)`)
 
+// Telnet - connection to the switch. If it implements SetReadDeadline(time.Time) error, the waiting for the output
+// is limited by the timeout of the client.
 type Telnet interface {
 	Connect(string, int) error
 	Close() error
@@ -44,8 +71,18 @@ type Client struct {
 	telnet    Telnet
 	connected bool
 	verbose   bool
-
-	info ClientInfo
+	prompt    string
+	banner    string  //the output of the switch after the login
+	driver    *Driver //nil until detected
+	rules     []DriverRule
+	hint      []string //the name and the platform of the switch known from its neighbors
+	paged     bool     //the paging of the output is enabled, the pages are scrolled by Run
+	timeout   time.Duration
+	port      int
+	session   *expect.Session
+	promptRe  *regexp.Regexp //the prompt at the end of the output of the command
+
+	info domain.DeviceReport
 }
 
 type Option func(*Client)
@@ -56,9 +93,33 @@ func WithVerbose() Option {
 	}
 }
 
+// WithTimeout limits the time of waiting for the output of the switch. The default is one minute.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithPort connects to the switches on the port instead of the standard telnet port,
+// e.g. to the ports of a terminal server
+func WithPort(port int) Option {
+	return func(c *Client) {
+		c.port = port
+	}
+}
+
+// WithDriverRules selects the driver of the switches by their names or platforms instead of the recognition
+func WithDriverRules(rules ...DriverRule) Option {
+	return func(c *Client) {
+		c.rules = rules
+	}
+}
+
 func NewClient(telnet Telnet, opts ...Option) *Client {
 	c := &Client{
-		telnet: telnet,
+		telnet:  telnet,
+		timeout: defaultTimeout,
+		port:    defaultPort,
 	}
 
 	for _, opt := range opts {
@@ -69,9 +130,11 @@ func NewClient(telnet Telnet, opts ...Option) *Client {
 }
 
 func (c *Client) Connect(address string, user string, password string) error {
-	c.info.Address = ""
-	c.info.Name = ""
-	c.info.Neighbors = c.info.Neighbors[:0]
+	c.info = domain.DeviceReport{}
+	c.prompt = ""
+	c.banner = ""
+	c.driver = nil
+	c.paged = true
 
 	if c.connected {
 		c.Close()
@@ -82,7 +145,7 @@ func (c *Client) Connect(address string, user string, password string) error {
 	}
 	c.info.Address = address
 
-	if err := c.telnet.Connect(address, defaultTelnetPort); err != nil {
+	if err := c.telnet.Connect(address, c.port); err != nil {
 		return fmt.Errorf("client connect [%v]: %w", address, err)
 	}
 
@@ -92,46 +155,56 @@ func (c *Client) Connect(address string, user string, password string) error {
 		fmt.Println()
 	}
 
-	var buffer [1]byte // Seems like the length of the buffer needs to be small, otherwise will have to wait for buffer to fill up.
-	var serverResponse bytes.Buffer
-	for {
-		n, err := c.telnet.Read(buffer[:])
-		if n <= 0 && nil == err {
-			continue
-		} else if n <= 0 && nil != err {
-			return fmt.Errorf("client connect [%v]: %w", address, err)
-		}
-
-		if c.verbose {
-			fmt.Print(string(buffer[:]))
-		}
+	sessionOpts := []expect.Option{expect.WithTimeout(c.timeout)}
+	if c.verbose {
+		sessionOpts = append(sessionOpts, expect.WithEcho(os.Stdout))
+	}
+	c.session = expect.NewSession(c.telnet, sessionOpts...)
+
+	passwordSent := false
+	sendPassword := c.answer(password + newLine)
+	match, err := c.session.Expect(
+		expect.Case{Name: "continue", Pattern: continuePromptRe, Handle: c.answer(newLine)},
+		expect.Case{Name: "user", Pattern: userPromptRe, Handle: c.answer(user + newLine)},
+		expect.Case{Name: "password", Pattern: passwordPromptRe, Handle: func(m expect.Match) (expect.Action, error) {
+			passwordSent = true
+			return sendPassword(m)
+		}},
+		expect.Case{Name: "failure", Pattern: failureMessageRe, Handle: fail(domain.ErrAuthentication)},
+		expect.Case{Name: "busy", Pattern: busyMessageRe, Handle: fail(domain.ErrDeviceBusy)},
+		expect.Case{Name: "timeout", Pattern: timeoutExpiredRe, Handle: fail(errTimeoutExpired)},
+		expect.Case{Name: "prompt", Pattern: anyPromptRe},
+	)
+	if passwordSent && errors.Is(err, io.EOF) { //before the credentials the closing is transient, e.g. all vty lines are busy
+		err = errClosedAfterPassword
+	}
+	if err != nil {
+		c.Close()
+		return fmt.Errorf("client connect [%v]: %w", address, err)
+	}
+	c.banner = match.Before + match.Text()
+	c.prompt = match.Groups[1]
+	c.promptRe = regexp.MustCompile(newLine + regexp.QuoteMeta(c.prompt) + "$")
+	c.info.Name = promptName(c.prompt)
 
-		serverResponse.WriteByte(buffer[0])
-		if strings.Contains(serverResponse.String(), txtUsername) ||
-			strings.Contains(serverResponse.String(), txtLogin) {
-			serverResponse.Reset()
-			c.telnet.Write([]byte(user + newLine))
-		} else if strings.Contains(serverResponse.String(), txtPassword) {
-			serverResponse.Reset()
-			c.telnet.Write([]byte(password + newLine))
-		} else if strings.Contains(serverResponse.String(), txtAuthenticationFailed) ||
-			strings.Contains(serverResponse.String(), txtLoginInvalid) ||
-			strings.Contains(serverResponse.String(), txtBadPasswords) {
-			serverResponse.Reset()
-			c.Close()
-			return fmt.Errorf("client connect [%v]: authentication error", address)
-		} else if strings.Contains(serverResponse.String(), txtTimeoutExpired) {
-			serverResponse.Reset()
-			c.Close()
-			return fmt.Errorf("client connect [%v]: timeout expired", address)
-		} else if strings.HasSuffix(serverResponse.String(), ">") {
-			break
-		}
+	if err := c.detectDriver(); err != nil {
+		c.Close()
+		return fmt.Errorf("client connect [%v]: %w", address, err)
+	}
+	if err := c.disablePaging(); err != nil {
+		c.Close()
+		return fmt.Errorf("client connect [%v]: %w", address, err)
 	}
 
 	return nil
 }
 
+// Hint sets the name and the platform of the switch known from its neighbors before the connection.
+// The driver rules are checked against them.
+func (c *Client) Hint(name string, platform string) {
+	c.hint = []string{name, platform}
+}
+
 func (c *Client) Close() error {
 	if c.connected == false {
 		return fmt.Errorf("client close [%v]: connection already closed", c.info.Address)
@@ -142,66 +215,176 @@ func (c *Client) Close() error {
 	return c.telnet.Close()
 }
 
-func (c *Client) Info() (ClientInfo, error) {
+func (c *Client) Info() (domain.DeviceReport, error) {
 	if !c.connected {
 		return c.info, fmt.Errorf("client info [%v]: connection closed", c.info.Address)
 	}
 
-	if _, err := c.telnet.Write([]byte(newLine)); err != nil {
+	output, err := c.Run(c.driver.Neighbors)
+	if errors.Is(err, ErrCommandRejected) { //e.g. CDP is disabled, the switch has no known neighbors
+		return c.info, nil
+	} else if err != nil {
 		return c.info, fmt.Errorf("client info [%v]: %w", c.info.Address, err)
 	}
 
-	var serverResponse bytes.Buffer
-	var buffer [1]byte // Seems like the length of the buffer needs to be small, otherwise will have to wait for buffer to fill up.
-	var isCmdStarted = false
-	for {
-		n, err := c.telnet.Read(buffer[:])
-		if n <= 0 && nil == err {
-			continue
-		} else if n <= 0 && nil != err {
-			return c.info, fmt.Errorf("client info [%v]: %w", c.info.Address, err)
+	c.info.Neighbors = c.driver.ParseNeighbors(output)
+
+	return c.info, nil
+}
+
+// Run executes the command and returns its output without the echo of the command and the prompt.
+// If the paging could not be disabled, the pages of the long output are scrolled automatically.
+func (c *Client) Run(command string) (string, error) {
+	if !c.connected {
+		return "", fmt.Errorf("client run [%v]: connection closed", c.info.Address)
+	}
+
+	if err := c.session.Send(command + newLine); err != nil {
+		return "", fmt.Errorf("client run [%v]: %w", c.info.Address, err)
+	}
+
+	var response strings.Builder
+	cases := []expect.Case{{Name: "prompt", Pattern: c.promptRe}}
+	if c.paged {
+		cases = append(cases, expect.Case{Name: "more", Pattern: morePromptRe, Handle: func(m expect.Match) (expect.Action, error) {
+			response.WriteString(m.Before)
+			return expect.Continue, c.session.Send(space)
+		}})
+	}
+	match, err := c.session.Expect(cases...)
+	if err != nil {
+		return "", fmt.Errorf("client run [%v]: %w", c.info.Address, err)
+	}
+	response.WriteString(match.Before + match.Text())
+
+	output := cleanTerminal(strings.TrimSuffix(response.String(), c.prompt))
+	if i := strings.Index(output, newLine); i >= 0 { //the first line is the echo of the command
+		output = output[i+1:]
+	}
+
+	if message, ok := rejected(output); ok {
+		return output, fmt.Errorf("client run [%v] %s: %w: %s", c.info.Address, command, ErrCommandRejected, message)
+	}
+
+	return output, nil
+}
+
+// answer returns the handler of the login prompt sending the text to the switch
+func (c *Client) answer(text string) func(expect.Match) (expect.Action, error) {
+	return func(expect.Match) (expect.Action, error) {
+		return expect.Continue, c.session.Send(text)
+	}
+}
+
+// fail returns the handler of the message stopping the login with the error
+func fail(err error) func(expect.Match) (expect.Action, error) {
+	return func(expect.Match) (expect.Action, error) {
+		return expect.Stop, err
+	}
+}
+
+// disablePaging turns off the paging of the output for the session, so that the output of the commands is read
+// in one pass. If the command is not permitted, the paging remains enabled.
+func (c *Client) disablePaging() error {
+	if c.driver.DisablePaging == "" {
+		return nil
+	}
+
+	_, err := c.Run(c.driver.DisablePaging)
+	if errors.Is(err, ErrCommandRejected) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	c.paged = false
+
+	return nil
+}
+
+// promptName returns the name of the switch from the prompt
+func promptName(prompt string) string {
+	return strings.TrimRight(strings.TrimLeft(prompt, "<["), txtPrompt+txtPrivilegedPrompt+"]"+space)
+}
+
+// detectDriver selects the driver of the switch by the rules, otherwise recognizes it by the banner and the prompt,
+// by "show version" or "display version". The version and the serial number of the switch are taken from the output
+// of the command, it is executed by the selected driver too. If nothing is recognized, the switch is considered IOS.
+func (c *Client) detectDriver() error {
+	if driver, ok := ruleDriver(c.rules, append(c.hint, c.info.Name)...); ok {
+		c.driver = driver
+	} else if driver, ok := recognizeDriver(c.banner); ok {
+		c.driver = driver
+	}
+	if c.driver != nil {
+		output, err := c.Run(c.driver.ShowVersion)
+		if errors.Is(err, ErrCommandRejected) {
+			return nil
+		} else if err != nil {
+			return err
 		}
+		c.readVersion(output)
+		return nil
+	}
 
-		if c.verbose {
-			fmt.Print(string(buffer[:]))
+	c.driver = DriverIOS
+	for _, command := range []string{cmdShowVersion, cmdDisplayVersion} {
+		output, err := c.Run(command)
+		if errors.Is(err, ErrCommandRejected) {
+			continue
+		} else if err != nil {
+			return err
 		}
 
-		serverResponse.WriteByte(buffer[0])
-		if strings.HasSuffix(serverResponse.String(), txtPrompt) {
-			if isCmdStarted == false {
-				c.info.Name = strings.TrimSpace(strings.TrimRight(serverResponse.String(), txtPrompt))
-				serverResponse.Reset()
+		c.driver, _ = recognizeDriver(output)
+		c.readVersion(output)
+		break
+	}
 
-				c.telnet.Write([]byte(cmdShowNeighbors + newLine))
-				isCmdStarted = true
-			} else {
-				input := strings.Replace(serverResponse.String()+newLine, cmdShowNeighbors, "", -1)
-				input = strings.TrimSpace(input)
-				input = strings.Replace(input, txtDeviceSeparator+newLine, "", 1)
-				serverResponse.Reset()
+	return nil
+}
 
-				c.info.Neighbors = parseInput(input)
+// readVersion takes the version and the serial number of the switch from the output of "show version"
+func (c *Client) readVersion(output string) {
+	if version, ok := shortVersion(output); ok {
+		c.info.Version = version
+	}
+	if serial := serialRe.FindStringSubmatch(output); serial != nil {
+		c.info.Serial = serial[1]
+	}
+}
+
+// cleanTerminal removes the remains of the paging prompts erased with backspaces and the escape sequences
+// of the terminal, e.g. " --More-- \b\b\b\b\b\b\b\b\b\b          \b\b\b\b\b\b\b\b\b\b" or "\x1b[42D"
+func cleanTerminal(output string) string {
+	output = pagingEraseRe.ReplaceAllString(output, "")
+
+	return escapeSequenceRe.ReplaceAllString(output, "")
+}
 
-				break
+// rejected checks whether the switch rejected the command, e.g. "% Invalid input detected at '^' marker."
+func rejected(output string) (string, bool) {
+	for _, line := range strings.Split(output, newLine) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Trim(line, "^") == "" { //the marker of the wrong position in the command
+			continue
+		}
+
+		for _, prefix := range rejectionPrefixes {
+			if strings.HasPrefix(line, prefix) {
+				return line, true
 			}
-		} else if strings.Contains(serverResponse.String(), txtMore) {
-			serverResponse.Truncate(serverResponse.Len() - len(txtMore))
-			c.telnet.Write([]byte(space))
 		}
+		return line, false
 	}
 
-	return c.info, nil
+	return "", false
 }
 
-func parseInput(in string) []ClientInfo {
-	var neighbors []ClientInfo
-	tokens := strings.Split(in, txtDeviceSeparator)
-	for _, t := range tokens {
-		res := re.FindStringSubmatch(t)
-		if res != nil {
-			neighbors = append(neighbors, ClientInfo{Name: res[1], Address: res[2]})
-		}
+// shortVersion extracts the version number from the text, e.g. 15.0(2)SE11 from the description of the software
+func shortVersion(text string) (string, bool) {
+	if version := shortVersionRe.FindStringSubmatch(text); version != nil {
+		return version[1], true
 	}
 
-	return neighbors
+	return "", false
 }
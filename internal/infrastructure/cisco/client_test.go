@@ -0,0 +1,556 @@
+package cisco
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/pkg/expect"
+)
+
+// fakeTelnet plays the switch: every written line is answered with the prepared response
+type fakeTelnet struct {
+	greeting  string
+	responses map[string]string
+	output    bytes.Buffer
+	written   []string
+	port      int
+}
+
+func (f *fakeTelnet) Connect(_ string, port int) error {
+	f.port = port
+	f.output.Reset()
+	f.output.WriteString(f.greeting)
+	return nil
+}
+
+func (f *fakeTelnet) Close() error {
+	return nil
+}
+
+func (f *fakeTelnet) Read(p []byte) (int, error) {
+	if f.output.Len() == 0 {
+		return 0, io.EOF
+	}
+	return f.output.Read(p)
+}
+
+func (f *fakeTelnet) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), newLine)
+	f.written = append(f.written, line)
+	f.output.WriteString(f.responses[line])
+	return len(p), nil
+}
+
+func newFakeSwitch(prompt string, commands map[string]string) *fakeTelnet {
+	responses := map[string]string{
+		"user":     "\r\nPassword: ",
+		"password": "\r\n" + prompt,
+		"":         "\r\n" + prompt,
+		" ":        "",
+	}
+	outputs := map[string]string{cmdShowVersion: iosVersionOutput} //the map of the caller is not modified
+	for _, driver := range drivers {
+		outputs[driver.DisablePaging] = ""
+	}
+	for command, output := range commands {
+		outputs[command] = output
+	}
+	for command, output := range outputs {
+		responses[command] = command + "\r\n" + output + "\r\n" + prompt
+	}
+
+	return &fakeTelnet{
+		greeting:  "\r\n##########\r\nUser Access Verification\r\n\r\nUsername: ",
+		responses: responses,
+	}
+}
+
+const iosVersionOutput = "Cisco IOS Software, C3750E Software (C3750E-UNIVERSALK9-M), Version 15.0(2)SE11, RELEASE SOFTWARE (fc3)\r\n" +
+	"Technical Support: http://www.cisco.com/techsupport\r\n" +
+	"\r\n" +
+	"ROM: Bootstrap program is C3750E boot loader\r\n" +
+	"SW1 uptime is 1 year, 2 weeks, 3 days, 4 hours, 5 minutes\r\n" +
+	"\r\n" +
+	"System serial number            : FOC1234X0AB\r\n"
+
+const cdpOutput = "-------------------------\r\n" +
+	"Device ID: SW2\r\n" +
+	"Entry address(es): \r\n" +
+	"  IP address: 192.168.1.2\r\n" +
+	"Platform: cisco WS-C2960-24TT-L,  Capabilities: Switch IGMP \r\n" +
+	"Interface: GigabitEthernet0/2,  Port ID (outgoing port): GigabitEthernet0/1\r\n" +
+	"Holdtime : 155 sec\r\n" +
+	"\r\n" +
+	"Version :\r\n" +
+	"Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 12.2(55)SE5, RELEASE SOFTWARE (fc1)\r\n" +
+	"\r\n" +
+	"advertisement version: 2\r\n" +
+	"Native VLAN: 10\r\n" +
+	"-------------------------\r\n" +
+	"Device ID: SW3\r\n" +
+	"Entry address(es): \r\n" +
+	"  IP address: 192.168.1.3\r\n" +
+	"Platform: cisco WS-C3750X-48,  Capabilities: Switch IGMP \r\n" +
+	"Interface: GigabitEthernet0/3,  Port ID (outgoing port): TenGigabitEthernet1/1/1\r\n"
+
+func TestClient_Info(t *testing.T) {
+	telnet := newFakeSwitch("SW1>", map[string]string{cmdShowNeighbors: cdpOutput})
+	client := NewClient(telnet)
+
+	if err := client.Connect("192.168.1.1", "user", "password"); err != nil {
+		t.Fatalf("Client.Connect() error = %v", err)
+	}
+	got, err := client.Info()
+	if err != nil {
+		t.Fatalf("Client.Info() error = %v", err)
+	}
+
+	want := domain.DeviceReport{
+		Name:    "SW1",
+		Address: "192.168.1.1",
+		Version: "15.0(2)SE11",
+		Serial:  "FOC1234X0AB",
+		Neighbors: []domain.NeighborReport{
+			{
+				Name: "SW2", Address: "192.168.1.2", Addresses: []string{"192.168.1.2"}, Platform: "WS-C2960-24TT-L", Version: "12.2(55)SE5",
+				LocalPort: "GigabitEthernet0/2", RemotePort: "GigabitEthernet0/1", NativeVLAN: 10,
+			},
+			{
+				Name: "SW3", Address: "192.168.1.3", Addresses: []string{"192.168.1.3"}, Platform: "WS-C3750X-48",
+				LocalPort: "GigabitEthernet0/3", RemotePort: "TenGigabitEthernet1/1/1",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Client.Info() = %v, want %v", got, want)
+	}
+}
+
+func TestClient_RunningConfig(t *testing.T) {
+	config := "Building configuration...\r\n" +
+		"\r\n" +
+		"Current configuration : 1234 bytes\r\n" +
+		"!\r\n" +
+		"! Last configuration change at 10:15:01 MSK Mon Oct 19 2026 by admin\r\n" +
+		"! NVRAM config last updated at 10:15:05 MSK Mon Oct 19 2026 by admin\r\n" +
+		"!\r\n" +
+		"version 12.2\r\n" +
+		"hostname SW1\r\n" +
+		" --More-- \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08          \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08interface GigabitEthernet0/1\r\n" +
+		" description uplink to SW2#\r\n" +
+		"!\r\n" +
+		"ntp clock-period 36028936\r\n" +
+		"ntp server 10.0.0.1\r\n" +
+		"end\r\n"
+	telnet := newFakeSwitch("SW1#", map[string]string{
+		cmdShowRunningConfig: config,
+		"terminal length 0":  "                  ^\r\n% Invalid input detected at '^' marker.\r\n", //the pages are scrolled
+	})
+	client := NewClient(telnet)
+
+	if err := client.Connect("192.168.1.1", "user", "password"); err != nil {
+		t.Fatalf("Client.Connect() error = %v", err)
+	}
+	got, err := client.RunningConfig()
+	if err != nil {
+		t.Fatalf("Client.RunningConfig() error = %v", err)
+	}
+
+	want := "!\n" +
+		"!\n" +
+		"version 12.2\n" +
+		"hostname SW1\n" +
+		"interface GigabitEthernet0/1\n" +
+		" description uplink to SW2#\n" +
+		"!\n" +
+		"ntp server 10.0.0.1\n" +
+		"end\n"
+	if got != want {
+		t.Errorf("Client.RunningConfig() = %q, want %q", got, want)
+	}
+}
+
+func TestClient_Paging(t *testing.T) {
+	const more = " --More-- \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08          \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08"
+	tests := []struct {
+		name      string
+		paging    string
+		output    string
+		wantSpace bool
+	}{
+		{"disabled", "", strings.Replace(cdpOutput, "Device ID: SW2", "\x1b[KDevice ID: SW2", 1), false},
+		{"not permitted", "% Invalid input detected at '^' marker.", strings.Replace(cdpOutput, "Device ID: SW2", more+"Device ID: SW2", 1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telnet := newFakeSwitch("SW1>", map[string]string{
+				"terminal length 0": tt.paging,
+				cmdShowNeighbors:    tt.output,
+			})
+			client := NewClient(telnet)
+
+			if err := client.Connect("192.168.1.1", "user", "password"); err != nil {
+				t.Fatalf("Client.Connect() error = %v", err)
+			}
+			got, err := client.Info()
+			if err != nil {
+				t.Fatalf("Client.Info() error = %v", err)
+			}
+
+			if len(got.Neighbors) != 2 || got.Neighbors[0].Name != "SW2" {
+				t.Errorf("Client.Info() = %v, want SW2 and SW3", got)
+			}
+			if space := strings.Contains(strings.Join(telnet.written, newLine), newLine+space); space != tt.wantSpace {
+				t.Errorf("the pages scrolled = %v, want %v", space, tt.wantSpace)
+			}
+		})
+	}
+}
+
+func TestClient_RunningConfigRejected(t *testing.T) {
+	telnet := newFakeSwitch("SW1>", map[string]string{
+		cmdShowRunningConfig: "                  ^\r\n% Invalid input detected at '^' marker.\r\n",
+	})
+	client := NewClient(telnet)
+	client.Connect("192.168.1.1", "user", "password")
+
+	if _, err := client.RunningConfig(); err == nil {
+		t.Errorf("Client.RunningConfig() error = nil, want the rejection of the command")
+	}
+}
+
+func TestClient_MACTable(t *testing.T) {
+	telnet := newFakeSwitch("SW1>", map[string]string{
+		cmdShowMACTable: "          ^\r\n% Invalid input detected at '^' marker.\r\n",
+		cmdShowMACTableOld: "          Mac Address Table\r\n" +
+			"-------------------------------------------\r\n" +
+			"\r\n" +
+			"Vlan    Mac Address       Type        Ports\r\n" +
+			"----    -----------       --------    -----\r\n" +
+			" All    0100.0ccc.cccc    STATIC      CPU\r\n" +
+			"   1    0011.2233.4455    DYNAMIC     Gi0/1\r\n" +
+			"  10    0011.2233.44AA    DYNAMIC     Fa0/5\r\n" +
+			"  10    0100.5e00.0001    STATIC      Fa0/5,Fa0/6\r\n" +
+			"*  20   0011.2233.44bb    dynamic  Yes          5   Gi1/1\r\n" +
+			"Total Mac Addresses for this criterion: 5\r\n",
+	})
+	client := NewClient(telnet)
+	client.Connect("192.168.1.1", "user", "password")
+
+	entries, err := client.MACTable()
+	if err != nil {
+		t.Fatalf("Client.MACTable() error = %v", err)
+	}
+
+	expect := []domain.MACEntry{
+		{MAC: "00:11:22:33:44:55", VLAN: 1, Port: "Gi0/1"},
+		{MAC: "00:11:22:33:44:aa", VLAN: 10, Port: "Fa0/5"},
+		{MAC: "00:11:22:33:44:bb", VLAN: 20, Port: "Gi1/1"},
+	}
+	if !reflect.DeepEqual(entries, expect) {
+		t.Errorf("Client.MACTable() = %v, want %v", entries, expect)
+	}
+}
+
+func TestClient_ARPTable(t *testing.T) {
+	telnet := newFakeSwitch("SW1>", map[string]string{
+		cmdShowARP: "Protocol  Address          Age (min)  Hardware Addr   Type   Interface\r\n" +
+			"Internet  192.168.1.1             -   0011.2233.0001  ARPA   Vlan1\r\n" +
+			"Internet  192.168.1.100          12   0011.2233.4455  ARPA   Vlan1\r\n" +
+			"Internet  192.168.1.101           0   Incomplete      ARPA\r\n",
+	})
+	client := NewClient(telnet)
+	client.Connect("192.168.1.1", "user", "password")
+
+	entries, err := client.ARPTable()
+	if err != nil {
+		t.Fatalf("Client.ARPTable() error = %v", err)
+	}
+
+	expect := []domain.ARPEntry{
+		{Address: "192.168.1.1", MAC: "00:11:22:33:00:01"},
+		{Address: "192.168.1.100", MAC: "00:11:22:33:44:55"},
+	}
+	if !reflect.DeepEqual(entries, expect) {
+		t.Errorf("Client.ARPTable() = %v, want %v", entries, expect)
+	}
+}
+
+func TestClient_PortChannels(t *testing.T) {
+	telnet := newFakeSwitch("SW1>", map[string]string{
+		cmdShowEtherChannel: "Flags:  D - down        P - bundled in port-channel\r\n" +
+			"        I - stand-alone s - suspended\r\n" +
+			"        U - in use      f - failed to allocate aggregator\r\n" +
+			"\r\n" +
+			"Number of channel-groups in use: 3\r\n" +
+			"Number of aggregators:           3\r\n" +
+			"\r\n" +
+			"Group  Port-channel  Protocol    Ports\r\n" +
+			"------+-------------+-----------+-----------------------------------------------\r\n" +
+			"1      Po1(SU)         LACP      Gi1/0/1(P)  Gi1/0/2(P)\r\n" +
+			"2      Po2(SD)          -\r\n" +
+			"10     Po10(SU)        PAgP      Gi1/0/3(P)  Gi1/0/4(P)  Gi1/0/5(D)\r\n" +
+			"                                 Gi1/0/6(P)\r\n",
+	})
+	client := NewClient(telnet)
+	client.Connect("192.168.1.1", "user", "password")
+
+	channels, err := client.PortChannels()
+	if err != nil {
+		t.Fatalf("Client.PortChannels() error = %v", err)
+	}
+
+	expect := []domain.PortChannel{
+		{Port: "Po1", Members: []string{"Gi1/0/1", "Gi1/0/2"}},
+		{Port: "Po2"},
+		{Port: "Po10", Members: []string{"Gi1/0/3", "Gi1/0/4", "Gi1/0/5", "Gi1/0/6"}},
+	}
+	if !reflect.DeepEqual(channels, expect) {
+		t.Errorf("Client.PortChannels() = %v, want %v", channels, expect)
+	}
+}
+
+func TestParseTrunks(t *testing.T) {
+	output := "\r\n" +
+		"Port        Mode             Encapsulation  Status        Native vlan\r\n" +
+		"Gi0/1       on               802.1q         trunking      1\r\n" +
+		"Gi0/2       desirable        n-802.1q       trunking      99\r\n" +
+		"\r\n" +
+		"Port        Vlans allowed on trunk\r\n" +
+		"Gi0/1       1-4094\r\n" +
+		"Gi0/2       10,20,30-39,\r\n" +
+		"            100\r\n" +
+		"\r\n" +
+		"Port        Vlans allowed and active in management domain\r\n" +
+		"Gi0/1       1,10,20\r\n" +
+		"Gi0/2       none\r\n" +
+		"\r\n" +
+		"Port        Vlans in spanning tree forwarding state and not pruned\r\n" +
+		"Gi0/1       1,10,20\r\n" +
+		"Gi0/2       none\r\n"
+
+	got, err := parseTrunks(output)
+	if err != nil {
+		t.Fatalf("parseTrunks() error = %v", err)
+	}
+
+	want := []domain.TrunkPort{
+		{Port: "Gi0/1", VLANs: domain.PortVLANs{
+			Native:  1,
+			Allowed: domain.VLANSet{{From: 1, To: 4094}},
+			Active:  domain.VLANSet{{From: 1, To: 1}, {From: 10, To: 10}, {From: 20, To: 20}},
+		}},
+		{Port: "Gi0/2", VLANs: domain.PortVLANs{
+			Native:  99,
+			Allowed: domain.VLANSet{{From: 10, To: 10}, {From: 20, To: 20}, {From: 30, To: 39}, {From: 100, To: 100}},
+			Active:  domain.VLANSet{},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTrunks() = %v, want %v", got, want)
+	}
+}
+
+func TestParseVLANs(t *testing.T) {
+	output := "\r\n" +
+		"VLAN Name                             Status    Ports\r\n" +
+		"---- -------------------------------- --------- -------------------------------\r\n" +
+		"1    default                          active    Fa0/1, Fa0/2, Fa0/3\r\n" +
+		"                                                Fa0/4\r\n" +
+		"10   USERS                            active    Fa0/5\r\n" +
+		"1002 fddi-default                     act/unsup \r\n"
+
+	want := []domain.VLAN{
+		{ID: 1, Name: "default", Status: "active"},
+		{ID: 10, Name: "USERS", Status: "active"},
+		{ID: 1002, Name: "fddi-default", Status: "act/unsup"},
+	}
+	if got := parseVLANs(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseVLANs() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSpanningTree(t *testing.T) {
+	output := "\r\n" +
+		"VLAN0001\r\n" +
+		"  Spanning tree enabled protocol ieee\r\n" +
+		"  Root ID    Priority    32769\r\n" +
+		"             Address     0011.2233.0001\r\n" +
+		"             This bridge is the root\r\n" +
+		"             Hello Time   2 sec  Max Age 20 sec  Forward Delay 15 sec\r\n" +
+		"\r\n" +
+		"  Bridge ID  Priority    32769  (priority 32768 sys-id-ext 1)\r\n" +
+		"             Address     0011.2233.0001\r\n" +
+		"\r\n" +
+		"Interface           Role Sts Cost      Prio.Nbr Type\r\n" +
+		"------------------- ---- --- --------- -------- --------------------------------\r\n" +
+		"Gi0/1               Desg FWD 4         128.1    P2p \r\n" +
+		"Gi0/2               Desg FWD 4         128.2    P2p \r\n" +
+		"\r\n" +
+		"VLAN0010\r\n" +
+		"  Spanning tree enabled protocol rstp\r\n" +
+		"  Root ID    Priority    4106\r\n" +
+		"             Address     0011.2233.00ff\r\n" +
+		"             Cost        4\r\n" +
+		"             Port        24 (GigabitEthernet0/24)\r\n" +
+		"\r\n" +
+		"  Bridge ID  Priority    32778  (priority 32768 sys-id-ext 10)\r\n" +
+		"             Address     0011.2233.0001\r\n" +
+		"\r\n" +
+		"Interface           Role Sts Cost      Prio.Nbr Type\r\n" +
+		"------------------- ---- --- --------- -------- --------------------------------\r\n" +
+		"Gi0/1               Desg FWD 4         128.1    P2p \r\n" +
+		"Gi0/2               Altn BLK 4         128.2    P2p \r\n" +
+		"Gi0/24              Root FWD 4         128.24   P2p \r\n" +
+		"\r\n" +
+		"MST0\r\n" +
+		"  Spanning tree enabled protocol mstp\r\n" +
+		"  Root ID    Priority    0\r\n" +
+		"             Address     0011.2233.00aa\r\n" +
+		"\r\n" +
+		"  Bridge ID  Priority    32768  (priority 32768 sys-id-ext 0)\r\n" +
+		"             Address     0011.2233.0001\r\n" +
+		"\r\n" +
+		"Interface           Role Sts Cost      Prio.Nbr Type\r\n" +
+		"------------------- ---- --- --------- -------- --------------------------------\r\n" +
+		"Po1                 Root FWD 1         128.65   P2p \r\n" +
+		"Gi0/3               Back BLK 4         128.3    P2p \r\n"
+
+	want := []domain.STPInstance{
+		{Instance: "VLAN0001", RootPriority: 32769, RootMAC: "00:11:22:33:00:01", BridgeMAC: "00:11:22:33:00:01", IsRoot: true},
+		{
+			Instance: "VLAN0010", RootPriority: 4106, RootMAC: "00:11:22:33:00:ff", BridgeMAC: "00:11:22:33:00:01",
+			RootPort: "Gi0/24", BlockedPorts: []string{"Gi0/2"},
+		},
+		{
+			Instance: "MST0", RootPriority: 0, RootMAC: "00:11:22:33:00:aa", BridgeMAC: "00:11:22:33:00:01",
+			RootPort: "Po1", BlockedPorts: []string{"Gi0/3"},
+		},
+	}
+	if got := parseSpanningTree(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSpanningTree() = %v, want %v", got, want)
+	}
+}
+
+// byteReader returns the output of the switch byte by byte like a slow connection
+type byteReader struct {
+	*fakeTelnet
+}
+
+func (r byteReader) Read(p []byte) (int, error) {
+	return r.fakeTelnet.Read(p[:1])
+}
+
+// BenchmarkClient_Info crawls a switch with the neighbors output of 5000 lines
+func BenchmarkClient_Info(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; sb.Len() == 0 || strings.Count(sb.String(), "\r\n") < 5000; i++ {
+		fmt.Fprintf(&sb, "-------------------------\r\n"+
+			"Device ID: SW%d\r\n"+
+			"Entry address(es): \r\n"+
+			"  IP address: 10.0.%d.%d\r\n"+
+			"Platform: cisco WS-C2960-24TT-L,  Capabilities: Switch IGMP \r\n"+
+			"Interface: GigabitEthernet0/%d,  Port ID (outgoing port): GigabitEthernet0/1\r\n"+
+			"Holdtime : 155 sec\r\n"+
+			"\r\n"+
+			"Version :\r\n"+
+			"Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 12.2(55)SE5, RELEASE SOFTWARE (fc1)\r\n", i, i/250, i%250, i%48)
+	}
+	output := sb.String()
+
+	benchmarks := []struct {
+		name   string
+		telnet func(*fakeTelnet) Telnet
+	}{
+		{"chunks", func(f *fakeTelnet) Telnet { return f }},
+		{"byte by byte", func(f *fakeTelnet) Telnet { return byteReader{f} }},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(output)))
+			for i := 0; i < b.N; i++ {
+				client := NewClient(bm.telnet(newFakeSwitch("SW1>", map[string]string{cmdShowNeighbors: output})))
+				if err := client.Connect("192.168.1.1", "user", "password"); err != nil {
+					b.Fatal(err)
+				}
+				info, err := client.Info()
+				if err != nil || len(info.Neighbors) != 500 {
+					b.Fatalf("Client.Info() = %d neighbors, %v", len(info.Neighbors), err)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_ConnectPort(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"default", nil, 23},
+		{"terminal server", []Option{WithPort(2003)}, 2003},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telnet := newFakeSwitch("SW1#", map[string]string{})
+			if err := NewClient(telnet, tt.opts...).Connect("192.168.1.1", "user", "password"); err != nil {
+				t.Fatalf("Client.Connect() error = %v", err)
+			}
+			if telnet.port != tt.want {
+				t.Errorf("port = %d, want %d", telnet.port, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ConnectErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		greeting string
+		password string
+		wantErr  error
+	}{
+		{"authentication", "\r\nUsername: ", "\r\n% Authentication failed\r\n\r\nUsername: ", domain.ErrAuthentication},
+		{"busy", "\r\n% Connection refused by remote host\r\n", "", domain.ErrDeviceBusy},
+		{"login timeout", "\r\nUsername: ", "\r\n% Password:  timeout expired!\r\n", expect.ErrTimeout},
+		{"closed before login", "", "", io.EOF},
+		{"closed after password", "\r\nUsername: ", "", domain.ErrAuthentication},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telnet := newFakeSwitch("SW1#", map[string]string{})
+			telnet.greeting = tt.greeting
+			telnet.responses["password"] = tt.password
+
+			err := NewClient(telnet).Connect("192.168.1.1", "user", "password")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr != domain.ErrAuthentication && errors.Is(err, domain.ErrAuthentication)) {
+				t.Errorf("Client.Connect() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_ConnectBanner(t *testing.T) {
+	//the words of the login prompts in the banner after the login are not answered
+	telnet := newFakeSwitch("N5K-1#", map[string]string{})
+	telnet.responses["password"] = "\r\nLast login: Mon Oct 19 10:00:00 2026 from 10.0.0.1\r\n" +
+		"Cisco Nexus Operating System (NX-OS) Software\r\nN5K-1#"
+
+	client := NewClient(telnet)
+	if err := client.Connect("192.168.1.1", "user", "password"); err != nil {
+		t.Fatalf("Client.Connect() error = %v", err)
+	}
+	if want := []string{"user", "password", cmdShowVersion, "terminal length 0"}; !reflect.DeepEqual(telnet.written, want) {
+		t.Errorf("written = %q, want %q", telnet.written, want)
+	}
+}
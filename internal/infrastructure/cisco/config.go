@@ -0,0 +1,43 @@
+package cisco
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const cmdShowRunningConfig = "show running-config"
+
+// volatileConfigRe - lines of the configuration that change without changing the configuration itself
+var volatileConfigRe = regexp.MustCompile(`^(Building configuration\.\.\.|Current configuration : \d+ bytes|! Last configuration change at .*|! NVRAM config last updated at .*|! No configuration change since last restart|ntp clock-period .*)$`)
+
+// RunningConfig returns the current configuration of the switch without the volatile lines.
+// The user must have the privileges to view the configuration.
+func (c *Client) RunningConfig() (string, error) {
+	output, err := c.Run(cmdShowRunningConfig)
+	if err != nil {
+		return "", fmt.Errorf("client running config [%v]: %w", c.info.Address, err)
+	}
+
+	return cleanConfig(output), nil
+}
+
+// cleanConfig removes the volatile lines and the empty lines at the beginning of the configuration
+func cleanConfig(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", newLine), newLine)
+
+	var sb strings.Builder
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \r")
+		if volatileConfigRe.MatchString(line) {
+			continue
+		}
+		if sb.Len() == 0 && line == "" {
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteString(newLine)
+	}
+
+	return strings.TrimRight(sb.String(), newLine) + newLine
+}
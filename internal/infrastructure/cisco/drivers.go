@@ -0,0 +1,232 @@
+package cisco
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// Driver - dialect of the command line of the switch: the prompts of the login, the paging of the long output
+// and the discovery of the neighbors
+type Driver struct {
+	Name            string
+	UserPrompts     []string       //the requests of the user name
+	PasswordPrompts []string       //the requests of the password
+	ContinuePrompts []string       //the requests to press a key before the login
+	FailureMessages []string       //the messages of the failed login
+	BusyMessages    []string       //the messages of the switch having no free sessions, e.g. all vty lines are busy
+	Prompt          *regexp.Regexp //the prompt of the command line
+	MorePrompts     []string       //the markers of the paged output
+	DisablePaging   string         //the command disabling the paging for the session
+	ShowVersion     string         //the command showing the version of the software
+	Neighbors       string         //the command showing the neighbors
+	ParseNeighbors  func(output string) []domain.NeighborReport
+
+	recognize func(text string) bool //recognizes the driver by the banner, the prompt or the output of "show version"
+}
+
+// DriverRule selects the driver of the switches whose name or platform matches the pattern
+type DriverRule struct {
+	Pattern *regexp.Regexp
+	Driver  *Driver
+}
+
+var ErrUnknownDriver = errors.New("unknown driver")
+
+var (
+	ciscoUserPrompts     = []string{"Username:", "Login:"}
+	ciscoPasswordPrompts = []string{"Password:"}
+	ciscoFailureMessages = []string{"Authentication failed", "Login invalid", "Bad passwords"}
+	ciscoBusyMessages    = []string{"Connection refused by remote host", "lines are busy"}
+)
+
+var (
+	DriverIOS = &Driver{
+		Name:            "ios",
+		UserPrompts:     ciscoUserPrompts,
+		PasswordPrompts: ciscoPasswordPrompts,
+		FailureMessages: ciscoFailureMessages,
+		BusyMessages:    ciscoBusyMessages,
+		Prompt:          promptRe,
+		MorePrompts:     []string{txtMore},
+		DisablePaging:   "terminal length 0",
+		ShowVersion:     cmdShowVersion,
+		Neighbors:       cmdShowNeighbors,
+		ParseNeighbors:  parseIOSNeighbors,
+		recognize: func(text string) bool {
+			return strings.Contains(text, "Cisco IOS Software") || strings.Contains(text, "IOS (tm)")
+		},
+	}
+	DriverIOSXE = &Driver{
+		Name:            "ios-xe",
+		UserPrompts:     ciscoUserPrompts,
+		PasswordPrompts: ciscoPasswordPrompts,
+		FailureMessages: ciscoFailureMessages,
+		BusyMessages:    ciscoBusyMessages,
+		Prompt:          promptRe,
+		MorePrompts:     []string{txtMore},
+		DisablePaging:   "terminal length 0",
+		ShowVersion:     cmdShowVersion,
+		Neighbors:       cmdShowNeighbors,
+		ParseNeighbors:  parseIOSNeighbors,
+		recognize: func(text string) bool {
+			return strings.Contains(text, "IOS-XE") || strings.Contains(text, "IOS XE")
+		},
+	}
+	DriverNXOS = &Driver{
+		Name:            "nx-os",
+		UserPrompts:     append([]string{"login:"}, ciscoUserPrompts...),
+		PasswordPrompts: ciscoPasswordPrompts,
+		FailureMessages: append([]string{"Login incorrect"}, ciscoFailureMessages...),
+		BusyMessages:    ciscoBusyMessages,
+		Prompt:          promptRe,
+		MorePrompts:     []string{txtMore},
+		DisablePaging:   "terminal length 0",
+		ShowVersion:     cmdShowVersion,
+		Neighbors:       "show cdp neighbors detail",
+		ParseNeighbors:  parseNXOSNeighbors,
+		recognize: func(text string) bool {
+			return strings.Contains(text, "NX-OS") || strings.Contains(text, "Nexus Operating System")
+		},
+	}
+	DriverCatOS = &Driver{
+		Name:            "catos",
+		UserPrompts:     ciscoUserPrompts,
+		PasswordPrompts: append([]string{"Enter password:"}, ciscoPasswordPrompts...),
+		FailureMessages: ciscoFailureMessages,
+		BusyMessages:    ciscoBusyMessages,
+		Prompt:          catOSPromptRe,
+		MorePrompts:     []string{txtMore},
+		DisablePaging:   "set length 0",
+		ShowVersion:     cmdShowVersion,
+		Neighbors:       "show cdp neighbors detail",
+		ParseNeighbors:  parseCatOSNeighbors,
+		recognize: func(text string) bool {
+			return strings.Contains(text, "NmpSW") || strings.Contains(text, "McpSW")
+		},
+	}
+)
+
+// drivers - the known drivers in the order of the recognition. The more specific ones go first:
+// the output of IOS-XE contains "Cisco IOS Software" too.
+// The drivers of other vendors with a similar CLI are in the vendor_*.go files.
+var drivers = []*Driver{DriverNXOS, DriverIOSXE, DriverCatOS, DriverIOS, DriverAruba, DriverHuawei, DriverEltex}
+
+// the prompts of all drivers, e.g. to log in before the driver is known
+var (
+	userPromptRe     = anyDriverLogin(func(d *Driver) []string { return d.UserPrompts })
+	passwordPromptRe = anyDriverLogin(func(d *Driver) []string { return d.PasswordPrompts })
+	continuePromptRe = anyDriverLogin(func(d *Driver) []string { return d.ContinuePrompts })
+	failureMessageRe = anyDriver(func(d *Driver) []string { return d.FailureMessages })
+	busyMessageRe    = anyDriver(func(d *Driver) []string { return d.BusyMessages })
+	morePromptRe     = anyDriver(func(d *Driver) []string { return d.MorePrompts })
+	timeoutExpiredRe = regexp.MustCompile(regexp.QuoteMeta(txtTimeoutExpired))
+	anyPromptRe      = anyDriverPrompt()
+)
+
+// neverRe matches nothing, e.g. the prompts that no driver has
+var neverRe = regexp.MustCompile(`[^\s\S]`)
+
+// DriverByName returns the driver by its name, e.g. "huawei"
+func DriverByName(name string) (*Driver, error) {
+	for _, driver := range drivers {
+		if strings.EqualFold(driver.Name, name) {
+			return driver, nil
+		}
+	}
+
+	return nil, fmt.Errorf("driver [%s]: %w", name, ErrUnknownDriver)
+}
+
+// ParseDriverRule parses the rule in the form "regexp=driver", e.g. "^MES=eltex"
+func ParseDriverRule(rule string) (DriverRule, error) {
+	i := strings.LastIndex(rule, "=")
+	if i <= 0 {
+		return DriverRule{}, fmt.Errorf("driver rule [%s]: the rule must be in the form regexp=driver", rule)
+	}
+
+	pattern, err := regexp.Compile(rule[:i])
+	if err != nil {
+		return DriverRule{}, fmt.Errorf("driver rule [%s]: %w", rule, err)
+	}
+	driver, err := DriverByName(rule[i+1:])
+	if err != nil {
+		return DriverRule{}, fmt.Errorf("driver rule [%s]: %w", rule, err)
+	}
+
+	return DriverRule{Pattern: pattern, Driver: driver}, nil
+}
+
+// recognizeDriver recognizes the driver by the banner, the prompt or the output of "show version".
+// The second result is false if the text does not point to any driver.
+func recognizeDriver(text string) (*Driver, bool) {
+	for _, driver := range drivers {
+		if driver.recognize(text) {
+			return driver, true
+		}
+	}
+
+	return DriverIOS, false
+}
+
+// ruleDriver returns the driver of the first rule matching any of the names
+func ruleDriver(rules []DriverRule, names ...string) (*Driver, bool) {
+	for _, rule := range rules {
+		for _, name := range names {
+			if name != "" && rule.Pattern.MatchString(name) {
+				return rule.Driver, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// anyDriver returns the pattern matching any of the prompts of the drivers
+func anyDriver(prompts func(*Driver) []string) *regexp.Regexp {
+	var all []string
+	seen := make(map[string]bool)
+	for _, driver := range drivers {
+		for _, prompt := range prompts(driver) {
+			if !seen[prompt] {
+				seen[prompt] = true
+				all = append(all, regexp.QuoteMeta(prompt))
+			}
+		}
+	}
+	if len(all) == 0 {
+		return neverRe
+	}
+
+	return regexp.MustCompile(strings.Join(all, "|"))
+}
+
+// anyDriverLogin returns the pattern matching the login prompt of any driver at the end of the output, where the switch
+// waits for the answer. So the same words in the banners, e.g. "Last login: ...", are not taken for the prompt.
+func anyDriverLogin(prompts func(*Driver) []string) *regexp.Regexp {
+	re := anyDriver(prompts)
+	if re == neverRe {
+		return neverRe
+	}
+
+	return regexp.MustCompile(`(?:` + re.String() + `)[ \t]*$`)
+}
+
+// anyDriverPrompt returns the pattern matching the prompt of the command line of any driver at the end of the output.
+// The first group is the prompt.
+func anyDriverPrompt() *regexp.Regexp {
+	var all []string
+	for _, driver := range drivers {
+		all = append(all, strings.TrimSuffix(strings.TrimPrefix(driver.Prompt.String(), "^"), "$"))
+	}
+
+	return regexp.MustCompile(`(?:^|\n)\r*((?:` + strings.Join(all, "|") + `))$`)
+}
+
+// lastLine returns the last line of the text without the carriage return, e.g. the prompt at the end of the output
+func lastLine(text string) string {
+	return strings.TrimLeft(text[strings.LastIndex(text, newLine)+1:], "\r")
+}
@@ -0,0 +1,220 @@
+package cisco
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestRecognizeDriver(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   *Driver
+		wantOk bool
+	}{
+		{"ios", "Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 12.2(55)SE5", DriverIOS, true},
+		{"old ios", "IOS (tm) C2950 Software (C2950-I6Q4L2-M), Version 12.1(22)EA14", DriverIOS, true},
+		{"ios-xe", "Cisco IOS XE Software, Version 17.03.04\r\nCisco IOS Software [Amsterdam]", DriverIOSXE, true},
+		{"nx-os", "Cisco Nexus Operating System (NX-OS) Software\r\nTAC support: http://www.cisco.com/tac", DriverNXOS, true},
+		{"catos", "WS-C6509 Software, Version NmpSW: 8.4(1)", DriverCatOS, true},
+		{"aruba", "Image stamp:    /ws/swbuildm/rel_ukiah_qaoff/code/build/bom\r\n                Apr 1 2021", DriverAruba, true},
+		{"huawei prompt", "\r\nInfo: The max number of VTY users is 5.\r\n<hw-acc-1>", DriverHuawei, true},
+		{"huawei version", "Huawei Versatile Routing Platform Software\r\nVRP (R) software, Version 5.170", DriverHuawei, true},
+		{"eltex", "Active-image: flash://system/images/mes2300-4014-R1.ros", DriverEltex, true},
+		{"unknown", "\r\nSW1>", DriverIOS, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := recognizeDriver(tt.text)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("recognizeDriver() = %v, %v, want %v, %v", got.Name, ok, tt.want.Name, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestAnyDriverLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern *regexp.Regexp
+		text    string
+		want    bool
+	}{
+		{"user prompt", userPromptRe, "\r\nUser Access Verification\r\n\r\nUsername: ", true},
+		{"nx-os user prompt", userPromptRe, "\r\nN5K-1 login: ", true},
+		{"banner", userPromptRe, "\r\nLast login: Mon Oct 19 10:00:00 2026 from 10.0.0.1\r\n", false},
+		{"password prompt", passwordPromptRe, "\r\nPassword:", true},
+		{"no prompts", anyDriverLogin(func(*Driver) []string { return nil }), "SW1#", false},
+		{"no prompts, empty text", anyDriverLogin(func(*Driver) []string { return nil }), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pattern.MatchString(tt.text); got != tt.want {
+				t.Errorf("%v.MatchString(%q) = %v, want %v", tt.pattern, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDriverRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		match   string
+		want    *Driver
+		wantErr error
+	}{
+		{"name", "^MES=eltex", "MES2324B", DriverEltex, nil},
+		{"equal sign in pattern", "^a=b$=huawei", "a=b", DriverHuawei, nil},
+		{"unknown driver", "^MES=junos", "", nil, ErrUnknownDriver},
+		{"no driver", "^MES", "", nil, nil},
+		{"wrong pattern", "(=eltex", "", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDriverRule(tt.rule)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("ParseDriverRule() error = nil, want error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParseDriverRule() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDriverRule() error = %v", err)
+			}
+
+			driver, ok := ruleDriver([]DriverRule{got}, "", tt.match)
+			if !ok || driver != tt.want {
+				t.Errorf("ruleDriver(%s) = %v, %v, want %v", tt.match, driver, ok, tt.want.Name)
+			}
+		})
+	}
+}
+
+// TestClient_InfoDrivers crawls the fake switches of the different vendors. The driver is recognized by the prompt,
+// by the output of the version command or selected by the rules.
+func TestClient_InfoDrivers(t *testing.T) {
+	tests := []struct {
+		name        string
+		prompt      string
+		greeting    string
+		commands    map[string]string
+		neighbors   string
+		rules       []string
+		hint        string
+		wantName    string
+		wantVersion string
+		wantSerial  string
+	}{
+		{
+			name:   "catos",
+			prompt: "Console> ",
+			commands: map[string]string{
+				cmdShowVersion: "WS-C6509 Software, Version NmpSW: 8.4(1)\r\nCopyright (c) 1995-2004 by Cisco Systems\r\n" +
+					"\r\nHardware Version: 2.0  Model: WS-C6509  Serial #: SCA044903GE\r\n",
+			},
+			neighbors:   "catos_6509.txt",
+			wantName:    "Console",
+			wantVersion: "8.4(1)",
+			wantSerial:  "SCA044903GE",
+		},
+		{
+			name:   "aruba",
+			prompt: "aruba-acc-1# ",
+			//the switch asks to press any key before the login
+			greeting: "\r\nHewlett Packard Enterprise Development LP\r\n\r\nPress any key to continue",
+			commands: map[string]string{
+				cmdShowVersion: "Image stamp:    /ws/swbuildm/rel_ukiah_qaoff/code/build/bom\r\n" +
+					"                Apr 1 2021 10:00:00\r\n                WC.16.10.0009\r\n",
+			},
+			neighbors: "aruba_2930f.txt",
+			wantName:  "aruba-acc-1",
+		},
+		{
+			name:   "huawei",
+			prompt: "<hw-acc-1>",
+			commands: map[string]string{
+				cmdShowVersion: "              ^\r\nError: Unrecognized command found at '^' position.",
+				//the driver is recognized by the prompt, the version is taken from its command
+				cmdDisplayVersion: "Huawei Versatile Routing Platform Software\r\n" +
+					"VRP (R) software, Version 5.170 (S5720 V200R011C10SPC500)\r\n",
+			},
+			neighbors:   "huawei_s5720.txt",
+			wantName:    "hw-acc-1",
+			wantVersion: "5.170",
+		},
+		{
+			name:   "eltex by version",
+			prompt: "mes-acc-1#",
+			commands: map[string]string{
+				cmdShowVersion: "Active-image: flash://system/images/mes2300-4014-R1.ros\r\n  Version: 4.0.14",
+			},
+			neighbors:   "eltex_mes2324.txt",
+			wantName:    "mes-acc-1",
+			wantVersion: "4.0.14",
+		},
+		{
+			name:   "eltex by rule",
+			prompt: "mes-acc-1#",
+			commands: map[string]string{
+				cmdShowVersion: "% Unrecognized command",
+			},
+			neighbors: "eltex_mes2324.txt",
+			rules:     []string{"^WS-C=ios", "^MES=eltex"},
+			hint:      "MES2324B",
+			wantName:  "mes-acc-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := os.ReadFile(filepath.Join("testdata", "neighbors", tt.neighbors))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rules []DriverRule
+			for _, r := range tt.rules {
+				rule, err := ParseDriverRule(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rules = append(rules, rule)
+			}
+
+			driver, _ := recognizeDriver(tt.commands[cmdShowVersion] + "\r\n" + tt.prompt)
+			if len(rules) > 0 {
+				driver, _ = ruleDriver(rules, tt.hint)
+			}
+			tt.commands[driver.Neighbors] = string(output)
+			telnet := newFakeSwitch(tt.prompt, tt.commands)
+			if tt.greeting != "" {
+				telnet.greeting = tt.greeting
+				telnet.responses[""] = "\r\nUsername: "
+			}
+
+			client := NewClient(telnet, WithDriverRules(rules...))
+			client.Hint("", tt.hint)
+			if err := client.Connect("10.50.0.1", "user", "password"); err != nil {
+				t.Fatalf("Client.Connect() error = %v", err)
+			}
+			got, err := client.Info()
+			if err != nil {
+				t.Fatalf("Client.Info() error = %v", err)
+			}
+
+			want := driver.ParseNeighbors(string(output))
+			if got.Name != tt.wantName || got.Version != tt.wantVersion || got.Serial != tt.wantSerial || len(got.Neighbors) != len(want) || len(want) == 0 {
+				t.Errorf("Client.Info() = %v, want %s %s %s with %d neighbors", got, tt.wantName, tt.wantVersion, tt.wantSerial, len(want))
+			}
+		})
+	}
+}
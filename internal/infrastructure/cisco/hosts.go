@@ -0,0 +1,90 @@
+package cisco
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const (
+	cmdShowMACTable    = "show mac address-table"
+	cmdShowMACTableOld = "show mac-address-table" //IOS 12.1 and older
+	cmdShowARP         = "show ip arp"
+)
+
+// macEntryRe - the line of the MAC address table: vlan, MAC address, type, ..., port.
+// The optional "*" and the additional columns are printed by the Catalyst 4500/6500.
+var macEntryRe = regexp.MustCompile(`^\s*\*?\s*(\d+)\s+([0-9a-fA-F]{4}\.[0-9a-fA-F]{4}\.[0-9a-fA-F]{4})\s+\S+.*?\s(\S+)\s*$`)
+
+// arpEntryRe - the line of the ARP table: protocol, address, age, MAC address, type, interface
+var arpEntryRe = regexp.MustCompile(`^\s*Internet\s+(\S+)\s+\S+\s+([0-9a-fA-F]{4}\.[0-9a-fA-F]{4}\.[0-9a-fA-F]{4})\s`)
+
+// MACTable returns the MAC addresses learned on the ports of the switch.
+// The entries of the switch itself (CPU, Router, Drop) are skipped.
+func (c *Client) MACTable() ([]domain.MACEntry, error) {
+	output, err := c.Run(cmdShowMACTable)
+	if errors.Is(err, ErrCommandRejected) {
+		output, err = c.Run(cmdShowMACTableOld)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("client mac table [%v]: %w", c.info.Address, err)
+	}
+
+	return parseMACTable(output), nil
+}
+
+// ARPTable returns the ip addresses resolved by the switch
+func (c *Client) ARPTable() ([]domain.ARPEntry, error) {
+	output, err := c.Run(cmdShowARP)
+	if err != nil {
+		return nil, fmt.Errorf("client arp table [%v]: %w", c.info.Address, err)
+	}
+
+	return parseARPTable(output), nil
+}
+
+func parseMACTable(output string) []domain.MACEntry {
+	var entries []domain.MACEntry
+	for _, line := range strings.Split(output, newLine) {
+		res := macEntryRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if res == nil {
+			continue
+		}
+
+		port := res[3]
+		if !strings.ContainsAny(port, "0123456789") || strings.Contains(port, ",") { //CPU, Router or several ports of the multicast address
+			continue
+		}
+
+		mac, err := domain.NormalizeMAC(res[2])
+		if err != nil {
+			continue
+		}
+		vlan, _ := strconv.Atoi(res[1])
+		entries = append(entries, domain.MACEntry{MAC: mac, VLAN: vlan, Port: port})
+	}
+
+	return entries
+}
+
+func parseARPTable(output string) []domain.ARPEntry {
+	var entries []domain.ARPEntry
+	for _, line := range strings.Split(output, newLine) {
+		res := arpEntryRe.FindStringSubmatch(line)
+		if res == nil {
+			continue
+		}
+
+		mac, err := domain.NormalizeMAC(res[2])
+		if err != nil {
+			continue
+		}
+		entries = append(entries, domain.ARPEntry{Address: res[1], MAC: mac})
+	}
+
+	return entries
+}
@@ -0,0 +1,85 @@
+package cisco
+
+import (
+	"strconv"
+	"strings"
+)
+
+// splitBefore splits the text into the blocks starting with the marker at the beginning of a line.
+// The text before the first marker is dropped.
+func splitBefore(text string, marker string) []string {
+	var blocks []string
+	lines := strings.Split(text, newLine)
+	start := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), marker) {
+			continue
+		}
+		if start >= 0 {
+			blocks = append(blocks, strings.Join(lines[start:i], newLine))
+		}
+		start = i
+	}
+	if start >= 0 {
+		blocks = append(blocks, strings.Join(lines[start:], newLine))
+	}
+
+	return blocks
+}
+
+// splitColon splits the line "key : value" of the LLDP outputs, where the key may be padded with spaces
+func splitColon(line string) (string, string, bool) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+// colonValue returns the value of the first line with the key
+func colonValue(lines []string, key string) (string, bool) {
+	for _, line := range lines {
+		if k, value, ok := splitColon(line); ok && k == key {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// colonValues returns the values of all lines with the keys
+func colonValues(lines []string, keys ...string) []string {
+	var values []string
+	for _, line := range lines {
+		k, value, ok := splitColon(line)
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if k == key {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+func colonVLAN(lines []string, key string) int {
+	value, _ := colonValue(lines, key)
+	vlan, _ := strconv.Atoi(value)
+
+	return vlan
+}
+
+// firstVersion returns the version from the first line containing it
+func firstVersion(lines []string) string {
+	for _, line := range lines {
+		if version, ok := shortVersion(line); ok {
+			return version
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,204 @@
+package cisco
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const (
+	cmdShowVersion    = "show version"
+	cmdDisplayVersion = "display version" //the version of Huawei VRP
+
+	txtCatOSNeighbor   = "Port (Our Port):"
+	txtNXOSMgmtAddress = "Mgmt address(es):"
+)
+
+// parseIOSNeighbors parses "show cdp neighbors detail" of IOS and IOS-XE. IOS-XE may list several entry addresses
+// and the management addresses: the entry addresses go first.
+func parseIOSNeighbors(output string) []domain.NeighborReport {
+	var neighbors []domain.NeighborReport
+	for _, block := range strings.Split(normalizeNewLines(output), txtDeviceSeparator) {
+		lines := strings.Split(block, newLine)
+		name, ok := fieldValue(lines, "Device ID:")
+		if !ok {
+			continue
+		}
+		neighbor := domain.NeighborReport{Name: name}
+		neighbor.SetAddresses(cdpAddresses(lines, []string{"IP address:", "IPv6 address:"}, "Management address(es):")...)
+		if neighbor.Address == "" {
+			continue
+		}
+
+		neighbor.LocalPort, neighbor.RemotePort = parsePorts(block)
+		if platform, ok := fieldValue(lines, "Platform:"); ok {
+			neighbor.Platform = trimPlatform(platform)
+		}
+		neighbor.Version = versionAfter(lines, "Version :")
+		neighbor.NativeVLAN = nativeVLAN(lines)
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
+
+// parseNXOSNeighbors parses "show cdp neighbors detail" of NX-OS. The name is taken from "System Name:" because
+// "Device ID:" contains the serial number of the neighbor, e.g. "N5K-2(FOC1234)".
+func parseNXOSNeighbors(output string) []domain.NeighborReport {
+	var neighbors []domain.NeighborReport
+	for _, block := range strings.Split(normalizeNewLines(output), txtDeviceSeparator) {
+		lines := strings.Split(block, newLine)
+		deviceID, ok := fieldValue(lines, "Device ID:")
+		if !ok {
+			continue
+		}
+		addresses := cdpAddresses(lines, []string{"IPv4 Address:", "IPv6 Address:"}, txtNXOSMgmtAddress)
+
+		name, ok := fieldValue(lines, "System Name:")
+		if !ok || name == "" {
+			name = deviceID
+			if i := strings.Index(name, "("); i > 0 {
+				name = name[:i]
+			}
+		}
+
+		neighbor := domain.NeighborReport{Name: name}
+		neighbor.SetAddresses(addresses...)
+		if neighbor.Address == "" {
+			continue
+		}
+		neighbor.LocalPort, neighbor.RemotePort = parsePorts(block)
+		if platform, ok := fieldValue(lines, "Platform:"); ok {
+			neighbor.Platform = trimPlatform(platform)
+		}
+		neighbor.Version = versionAfter(lines, "Version:")
+		neighbor.NativeVLAN = nativeVLAN(lines)
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
+
+// parseCatOSNeighbors parses "show cdp neighbors detail" of CatOS. Every neighbor starts with the local port.
+func parseCatOSNeighbors(output string) []domain.NeighborReport {
+	var neighbors []domain.NeighborReport
+	for _, block := range strings.Split(normalizeNewLines(output), txtCatOSNeighbor)[1:] {
+		lines := strings.Split(txtCatOSNeighbor+block, newLine)
+		name, ok := fieldValue(lines, "Device-ID:")
+		if !ok {
+			continue
+		}
+		address, _ := fieldValue(lines, "IP Address:")
+		neighbor := domain.NeighborReport{Name: name}
+		neighbor.SetAddresses(address)
+		if neighbor.Address == "" {
+			continue
+		}
+
+		neighbor.LocalPort, _ = fieldValue(lines, txtCatOSNeighbor)
+		neighbor.RemotePort, _ = fieldValue(lines, "Port-ID (Port on Neighbors's Device):")
+		if platform, ok := fieldValue(lines, "Platform:"); ok {
+			neighbor.Platform = trimPlatform(platform)
+		}
+		neighbor.Version = versionAfter(lines, "Version:")
+		neighbor.NativeVLAN = nativeVLAN(lines)
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
+
+func normalizeNewLines(text string) string {
+	return strings.ReplaceAll(text, "\r\n", newLine)
+}
+
+// fieldValue returns the value of the first line starting with the label
+func fieldValue(lines []string, label string) (string, bool) {
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, label) {
+			return strings.TrimSpace(line[len(label):]), true
+		}
+	}
+
+	return "", false
+}
+
+// cdpAddresses returns the addresses with the labels, e.g. "IPv6 address: 2001:DB8::2  (global unicast)".
+// The addresses listed after the header of the management addresses follow the entry addresses.
+func cdpAddresses(lines []string, labels []string, managementHeader string) []string {
+	var entry, management []string
+	inManagement := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, managementHeader):
+			inManagement = true
+		case strings.HasSuffix(line, "address(es):"):
+			inManagement = false
+		default:
+			for _, label := range labels {
+				if !strings.HasPrefix(line, label) {
+					continue
+				}
+				fields := strings.Fields(line[len(label):])
+				if len(fields) == 0 {
+					break
+				}
+				if inManagement {
+					management = append(management, fields[0])
+				} else {
+					entry = append(entry, fields[0])
+				}
+			}
+		}
+	}
+
+	return append(entry, management...)
+}
+
+func parsePorts(block string) (string, string) {
+	if ports := portsRe.FindStringSubmatch(block); ports != nil {
+		return ports[1], ports[2]
+	}
+
+	return "", ""
+}
+
+// trimPlatform removes the capabilities following the platform and the vendor prefix
+func trimPlatform(platform string) string {
+	platform = strings.SplitN(platform, ",", 2)[0]
+
+	return strings.TrimSpace(strings.TrimPrefix(platform, "cisco "))
+}
+
+// versionAfter returns the version from the description of the software following the label
+func versionAfter(lines []string, label string) string {
+	for i, line := range lines {
+		if strings.TrimSpace(line) != label {
+			continue
+		}
+		var description string
+		for _, next := range lines[i+1:] {
+			if next = strings.TrimSpace(next); next == "" {
+				break
+			}
+			if version, ok := shortVersion(next); ok {
+				return version
+			}
+			if description == "" {
+				description = next
+			}
+		}
+		return description //the version number is not found, the description is the best guess
+	}
+
+	return ""
+}
+
+func nativeVLAN(lines []string) int {
+	value, _ := fieldValue(lines, "Native VLAN:")
+	vlan, _ := strconv.Atoi(value)
+
+	return vlan
+}
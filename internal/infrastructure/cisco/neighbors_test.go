@@ -0,0 +1,60 @@
+package cisco
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// TestNeighborParsers parses the outputs of the neighbors commands from testdata/neighbors/<driver>_<name>.txt
+// and compares the neighbors with <driver>_<name>.golden
+func TestNeighborParsers(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "neighbors", "*.txt"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no outputs in testdata/neighbors: %v", err)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".txt")
+		t.Run(name, func(t *testing.T) {
+			driver, err := DriverByName(strings.SplitN(name, "_", 2)[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := driver.ParseNeighbors(string(output))
+
+			golden := strings.TrimSuffix(file, ".txt") + ".golden"
+			if *update {
+				data, _ := json.MarshalIndent(got, "", "  ")
+				if err := os.WriteFile(golden, append(data, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []domain.NeighborReport
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parse() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cisco
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const (
+	cmdShowEtherChannel  = "show etherchannel summary"
+	cmdShowPortChannelNX = "show port-channel summary"
+)
+
+var (
+	// portChannelRe - the line of the group: number, port-channel with its flags, then the protocol and the members
+	portChannelRe = regexp.MustCompile(`^\s*\d+\s+(\S+?)\(\w+\)(.*)$`)
+	// portChannelMemberRe - the member port with its flags, e.g. Gi1/0/1(P)
+	portChannelMemberRe = regexp.MustCompile(`(\S+)\([A-Za-z]+\)`)
+)
+
+// PortChannels returns the port-channels of the switch and their member ports
+func (c *Client) PortChannels() ([]domain.PortChannel, error) {
+	cmd := cmdShowEtherChannel
+	if c.driver == DriverNXOS {
+		cmd = cmdShowPortChannelNX
+	}
+
+	output, err := c.Run(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("client port-channels [%v]: %w", c.info.Address, err)
+	}
+
+	return parsePortChannels(output), nil
+}
+
+// parsePortChannels parses the table of the summary. The members that do not fit the line continue on the next ones.
+func parsePortChannels(output string) []domain.PortChannel {
+	var channels []domain.PortChannel
+	var members string
+
+	for _, line := range strings.Split(output, newLine) {
+		line = strings.TrimRight(line, "\r ")
+
+		if res := portChannelRe.FindStringSubmatch(line); res != nil {
+			channels = append(channels, domain.PortChannel{Port: res[1]})
+			members = res[2]
+		} else if len(channels) > 0 && strings.HasPrefix(line, " ") {
+			members = line
+		} else {
+			continue
+		}
+
+		channel := &channels[len(channels)-1]
+		for _, res := range portChannelMemberRe.FindAllStringSubmatch(members, -1) {
+			channel.Members = append(channel.Members, res[1])
+		}
+	}
+
+	return channels
+}
@@ -0,0 +1,79 @@
+package cisco
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const cmdShowSpanningTree = "show spanning-tree"
+
+var (
+	stpInstanceRe = regexp.MustCompile(`^(VLAN\d+|MST\d+)\s*$`)
+	stpRootIDRe   = regexp.MustCompile(`^\s*Root ID\s+Priority\s+(\d+)`)
+	stpBridgeIDRe = regexp.MustCompile(`^\s*Bridge ID\s+Priority\s+(\d+)`)
+	stpAddressRe  = regexp.MustCompile(`^\s*Address\s+([0-9a-fA-F]{4}\.[0-9a-fA-F]{4}\.[0-9a-fA-F]{4})`)
+	stpPortRe     = regexp.MustCompile(`^(\S+)\s+(Root|Desg|Altn|Back|Mstr|Shr|Disa|None)\s+(\S+)`)
+)
+
+// SpanningTree returns the state of every spanning tree instance of the switch (PVST+, Rapid PVST+ or MST)
+func (c *Client) SpanningTree() ([]domain.STPInstance, error) {
+	output, err := c.Run(cmdShowSpanningTree)
+	if err != nil {
+		return nil, fmt.Errorf("client spanning tree [%v]: %w", c.info.Address, err)
+	}
+
+	return parseSpanningTree(output), nil
+}
+
+// parseSpanningTree parses the sections of the instances. Every section describes the root bridge,
+// the switch itself and the roles and states of the ports.
+func parseSpanningTree(output string) []domain.STPInstance {
+	var instances []domain.STPInstance
+	var instance *domain.STPInstance
+	var block string //"root" or "bridge": the identifier whose address follows
+
+	for _, line := range strings.Split(output, newLine) {
+		line = strings.TrimRight(line, "\r ")
+
+		if res := stpInstanceRe.FindStringSubmatch(line); res != nil {
+			instances = append(instances, domain.STPInstance{Instance: res[1]})
+			instance = &instances[len(instances)-1] //valid until the next instance is appended
+			block = ""
+			continue
+		}
+		if instance == nil {
+			continue
+		}
+
+		if res := stpRootIDRe.FindStringSubmatch(line); res != nil {
+			instance.RootPriority, _ = strconv.Atoi(res[1])
+			block = "root"
+		} else if stpBridgeIDRe.MatchString(line) {
+			block = "bridge"
+		} else if res := stpAddressRe.FindStringSubmatch(line); res != nil {
+			mac, _ := domain.NormalizeMAC(res[1])
+			switch block {
+			case "root":
+				instance.RootMAC = mac
+			case "bridge":
+				instance.BridgeMAC = mac
+			}
+		} else if strings.Contains(line, "This bridge is the root") {
+			instance.IsRoot = true
+		} else if res := stpPortRe.FindStringSubmatch(line); res != nil {
+			port, role, state := res[1], res[2], res[3]
+			if role == "Root" {
+				instance.RootPort = port
+			}
+			if strings.HasPrefix(state, "BLK") || strings.HasPrefix(state, "BKN") || role == "Altn" || role == "Back" {
+				instance.BlockedPorts = append(instance.BlockedPorts, port)
+			}
+		}
+	}
+
+	return instances
+}
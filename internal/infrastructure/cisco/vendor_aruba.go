@@ -0,0 +1,64 @@
+package cisco
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// DriverAruba - HP ProCurve and Aruba OS-Switch. The CLI is similar to IOS, so the switches are polled
+// by the same client. The neighbors are discovered by LLDP.
+var DriverAruba = &Driver{
+	Name:            "aruba",
+	UserPrompts:     []string{"Username:", "login as:"},
+	PasswordPrompts: []string{"Password:"},
+	ContinuePrompts: []string{"Press any key to continue"},
+	FailureMessages: []string{"Invalid password", "Invalid username"},
+	Prompt:          arubaPromptRe,
+	MorePrompts:     []string{"-- MORE --"},
+	DisablePaging:   "no page",
+	ShowVersion:     cmdShowVersion,
+	Neighbors:       "show lldp info remote-device detail",
+	ParseNeighbors:  parseArubaNeighbors,
+	recognize: func(text string) bool {
+		return strings.Contains(text, "ProCurve") || strings.Contains(text, "ArubaOS") ||
+			strings.Contains(text, "Image stamp:")
+	},
+}
+
+var arubaPromptRe = regexp.MustCompile(`^[A-Za-z0-9_.\-() ]+[>#] ?$`)
+
+const txtArubaNeighbor = "Local Port"
+
+// arubaRevisionRe - the version of the software in the system description of Aruba, e.g. "revision WC.16.10.0009"
+var arubaRevisionRe = regexp.MustCompile(`revision ([^,\s]+)`)
+
+// parseArubaNeighbors parses "show lldp info remote-device detail" of HP ProCurve and Aruba OS-Switch.
+// The platform and the version are taken from the system description.
+func parseArubaNeighbors(output string) []domain.NeighborReport {
+	var neighbors []domain.NeighborReport
+	for _, block := range splitBefore(normalizeNewLines(output), txtArubaNeighbor) {
+		lines := strings.Split(block, newLine)
+		var neighbor domain.NeighborReport
+		neighbor.SetAddresses(colonValues(lines, "Address")...) //the remote management addresses
+		if neighbor.Address == "" {
+			continue
+		}
+		neighbor.Name, _ = colonValue(lines, "SysName")
+		neighbor.LocalPort, _ = colonValue(lines, txtArubaNeighbor)
+		neighbor.RemotePort, _ = colonValue(lines, "PortId")
+		if description, ok := colonValue(lines, "System Descr"); ok {
+			neighbor.Platform = strings.TrimSpace(strings.SplitN(description, ",", 2)[0])
+			if revision := arubaRevisionRe.FindStringSubmatch(description); revision != nil {
+				neighbor.Version = revision[1]
+			} else {
+				neighbor.Version = firstVersion([]string{description})
+			}
+		}
+		neighbor.NativeVLAN = colonVLAN(lines, "Pvid")
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
@@ -0,0 +1,52 @@
+package cisco
+
+import (
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// DriverEltex - Eltex MES. The CLI is similar to IOS, so the switches are polled by the same client.
+// The neighbors are discovered by LLDP.
+var DriverEltex = &Driver{
+	Name:            "eltex",
+	UserPrompts:     []string{"User Name:", "login:"},
+	PasswordPrompts: []string{"Password:"},
+	FailureMessages: []string{"Authentication failed", "% Bad username or password"},
+	Prompt:          promptRe,
+	MorePrompts:     []string{"More: <space>"},
+	DisablePaging:   "terminal datadump",
+	ShowVersion:     cmdShowVersion,
+	Neighbors:       "show lldp neighbors detail",
+	ParseNeighbors:  parseEltexNeighbors,
+	recognize: func(text string) bool {
+		return strings.Contains(text, "Eltex") || strings.Contains(text, "Active-image:")
+	},
+}
+
+const txtEltexNeighbor = "Local port:"
+
+// parseEltexNeighbors parses "show lldp neighbors detail" of Eltex MES. Every neighbor starts with the local port.
+func parseEltexNeighbors(output string) []domain.NeighborReport {
+	var neighbors []domain.NeighborReport
+	for _, block := range splitBefore(normalizeNewLines(output), txtEltexNeighbor) {
+		lines := strings.Split(block, newLine)
+		var neighbor domain.NeighborReport
+		neighbor.SetAddresses(colonValues(lines, "Management address")...)
+		if neighbor.Address == "" {
+			continue
+		}
+		neighbor.Name, _ = colonValue(lines, "System Name")
+		neighbor.LocalPort, _ = colonValue(lines, "Local port")
+		neighbor.RemotePort, _ = colonValue(lines, "Port ID")
+		if description, ok := colonValue(lines, "System description"); ok {
+			if fields := strings.Fields(description); len(fields) > 0 {
+				neighbor.Platform = fields[0]
+			}
+			neighbor.Version = firstVersion([]string{description})
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors
+}
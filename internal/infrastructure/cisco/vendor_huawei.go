@@ -0,0 +1,69 @@
+package cisco
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// DriverHuawei - Huawei VRP. The neighbors are discovered by LLDP.
+var DriverHuawei = &Driver{
+	Name:            "huawei",
+	UserPrompts:     []string{"Username:"},
+	PasswordPrompts: []string{"Password:"},
+	FailureMessages: []string{"Error: Local authentication is rejected", "Error: Username or password error",
+		"Error: Authentication fail"},
+	Prompt:         huaweiPromptRe,
+	MorePrompts:    []string{"---- More ----"},
+	DisablePaging:  "screen-length 0 temporary",
+	ShowVersion:    cmdDisplayVersion,
+	Neighbors:      "display lldp neighbor",
+	ParseNeighbors: parseHuaweiNeighbors,
+	recognize: func(text string) bool {
+		return strings.Contains(text, "Huawei Versatile Routing Platform") || huaweiPromptRe.MatchString(lastLine(text))
+	},
+}
+
+var huaweiPromptRe = regexp.MustCompile(`^[<\[][A-Za-z0-9_.\-:/()@~]+[>\]]$`)
+
+// huaweiNeighborRe - the header of the neighbors of the local port in "display lldp neighbor"
+var huaweiNeighborRe = regexp.MustCompile(`(?m)^(\S+) has \d+ neighbor\(s\):\s*$`)
+
+// parseHuaweiNeighbors parses "display lldp neighbor" of Huawei VRP. The neighbors are listed under the headers
+// of the local ports. The first line of the system description is the platform.
+func parseHuaweiNeighbors(output string) []domain.NeighborReport {
+	output = normalizeNewLines(output)
+
+	var neighbors []domain.NeighborReport
+	headers := huaweiNeighborRe.FindAllStringSubmatchIndex(output, -1)
+	for i, header := range headers {
+		end := len(output)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		localPort := output[header[2]:header[3]]
+
+		for _, block := range splitBefore(output[header[1]:end], "Neighbor index") {
+			lines := strings.Split(block, newLine)
+			neighbor := domain.NeighborReport{LocalPort: localPort}
+			neighbor.SetAddresses(colonValues(lines, "Management address", "Management address value")...) //VRP 5 and VRP 8
+			if neighbor.Address == "" {
+				continue
+			}
+			neighbor.Name, _ = colonValue(lines, "System name")
+			neighbor.RemotePort, _ = colonValue(lines, "Port ID")
+			for j, line := range lines {
+				if key, description, ok := splitColon(line); ok && key == "System description" {
+					neighbor.Platform = description
+					neighbor.Version = firstVersion(lines[j+1:])
+					break
+				}
+			}
+			neighbor.NativeVLAN = colonVLAN(lines, "Port VLAN ID(PVID)")
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	return neighbors
+}
@@ -0,0 +1,127 @@
+package cisco
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const (
+	cmdShowTrunks = "show interfaces trunk"
+	cmdShowVLANs  = "show vlan brief"
+)
+
+// trunkSectionRe - the headers of the sections of "show interfaces trunk"
+var trunkSectionRe = regexp.MustCompile(`^Port\s+(Mode|Vlans allowed on trunk|Vlans allowed and active|Vlans in spanning tree)`)
+
+// vlanRe - the line of "show vlan brief": id, name, status
+var vlanRe = regexp.MustCompile(`^(\d+)\s+(\S+)\s+(\S+)`)
+
+// Trunks returns the native and allowed VLANs of the trunk ports of the switch
+func (c *Client) Trunks() ([]domain.TrunkPort, error) {
+	output, err := c.Run(cmdShowTrunks)
+	if err != nil {
+		return nil, fmt.Errorf("client trunks [%v]: %w", c.info.Address, err)
+	}
+
+	return parseTrunks(output)
+}
+
+// VLANs returns the VLAN database of the switch
+func (c *Client) VLANs() ([]domain.VLAN, error) {
+	output, err := c.Run(cmdShowVLANs)
+	if err != nil {
+		return nil, fmt.Errorf("client vlans [%v]: %w", c.info.Address, err)
+	}
+
+	return parseVLANs(output), nil
+}
+
+// parseTrunks parses the sections of "show interfaces trunk". The long lists of VLANs are continued on the next
+// lines that begin with spaces.
+func parseTrunks(output string) ([]domain.TrunkPort, error) {
+	var ports []string
+	natives := make(map[string]int)
+	allowed := make(map[string]string)
+	active := make(map[string]string)
+
+	var section, lastPort string
+	for _, line := range strings.Split(output, newLine) {
+		line = strings.TrimRight(line, "\r ")
+		if res := trunkSectionRe.FindStringSubmatch(line); res != nil {
+			section, lastPort = res[1], ""
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			lastPort = ""
+			continue
+		}
+
+		if strings.HasPrefix(line, " ") { //the continuation of the list of VLANs
+			switch {
+			case lastPort == "":
+			case section == "Vlans allowed on trunk":
+				allowed[lastPort] += fields[0]
+			case section == "Vlans allowed and active":
+				active[lastPort] += fields[0]
+			}
+			continue
+		}
+
+		port := fields[0]
+		lastPort = port
+		switch section {
+		case "Mode":
+			ports = append(ports, port)
+			natives[port], _ = strconv.Atoi(fields[len(fields)-1])
+		case "Vlans allowed on trunk":
+			if len(fields) > 1 {
+				allowed[port] = fields[1]
+			}
+		case "Vlans allowed and active":
+			if len(fields) > 1 {
+				active[port] = fields[1]
+			}
+		}
+	}
+
+	trunks := make([]domain.TrunkPort, 0, len(ports))
+	for _, port := range ports {
+		vlans := domain.PortVLANs{Native: natives[port]}
+
+		var err error
+		if list, ok := allowed[port]; ok {
+			if vlans.Allowed, err = domain.ParseVLANSet(list); err != nil {
+				return nil, err
+			}
+		}
+		if list, ok := active[port]; ok {
+			if vlans.Active, err = domain.ParseVLANSet(list); err != nil {
+				return nil, err
+			}
+		}
+		trunks = append(trunks, domain.TrunkPort{Port: port, VLANs: vlans})
+	}
+
+	return trunks, nil
+}
+
+func parseVLANs(output string) []domain.VLAN {
+	var vlans []domain.VLAN
+	for _, line := range strings.Split(output, newLine) {
+		res := vlanRe.FindStringSubmatch(line)
+		if res == nil {
+			continue
+		}
+
+		id, _ := strconv.Atoi(res[1])
+		vlans = append(vlans, domain.VLAN{ID: id, Name: res[2], Status: res[3]})
+	}
+
+	return vlans
+}
@@ -0,0 +1,196 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// defaultSiteRe takes the site from the first part of the host name, for example "msk" from "msk-core-1"
+var defaultSiteRe = regexp.MustCompile(`^([A-Za-z0-9]+)[-_.]`)
+
+var plainYAMLRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.\-/]*$`)
+
+// AnsibleFormat - format of the Ansible inventory
+type AnsibleFormat string
+
+const (
+	AnsibleYAML AnsibleFormat = "yaml"
+	AnsibleINI  AnsibleFormat = "ini"
+)
+
+// Ansible renders the network as an Ansible inventory. The hosts are grouped by platform, software version,
+// site and crawl depth. Switches discarded by the filter or failed during the crawl are not included.
+type Ansible struct {
+	format AnsibleFormat
+	siteRe *regexp.Regexp
+}
+
+type AnsibleOption func(*Ansible)
+
+func WithAnsibleFormat(format AnsibleFormat) AnsibleOption {
+	return func(a *Ansible) {
+		a.format = format
+	}
+}
+
+// WithAnsibleSiteRegexp sets the regular expression whose first group extracts the site from the host name
+func WithAnsibleSiteRegexp(re *regexp.Regexp) AnsibleOption {
+	return func(a *Ansible) {
+		a.siteRe = re
+	}
+}
+
+func NewAnsible(opts ...AnsibleOption) *Ansible {
+	a := &Ansible{
+		format: AnsibleYAML,
+		siteRe: defaultSiteRe,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+type ansibleHost struct {
+	name    string
+	address string
+}
+
+func (a *Ansible) Render(w io.Writer, network *domain.Network) error {
+	hosts, groups := a.inventory(network)
+
+	bw := bufio.NewWriter(w)
+	switch a.format {
+	case AnsibleYAML:
+		writeAnsibleYAML(bw, hosts, groups)
+	case AnsibleINI:
+		writeAnsibleINI(bw, hosts, groups)
+	default:
+		return fmt.Errorf("ansible render: unknown format %s", a.format)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("ansible render: %w", err)
+	}
+
+	return nil
+}
+
+// inventory returns the hosts and the names of the hosts of each group
+func (a *Ansible) inventory(network *domain.Network) ([]ansibleHost, map[string][]string) {
+	var hosts []ansibleHost
+	groups := make(map[string][]string)
+	used := make(map[string]bool)
+
+	for _, sw := range network.Switches() {
+		if sw.Status() == domain.StatusDiscarded || sw.Status() == domain.StatusFailed {
+			continue
+		}
+
+		name := ansibleHostName(switchName(sw))
+		if used[name] {
+			name = ansibleHostName(name + "_" + sw.Address())
+		}
+		used[name] = true
+		hosts = append(hosts, ansibleHost{name: name, address: sw.Address()})
+
+		if sw.Platform() != "" {
+			addToGroup(groups, "platform_"+sw.Platform(), name)
+		}
+		if sw.Version() != "" {
+			addToGroup(groups, "version_"+sw.Version(), name)
+		}
+		if site := a.siteRe.FindStringSubmatch(sw.Name()); len(site) > 1 {
+			addToGroup(groups, "site_"+site[1], name)
+		}
+		addToGroup(groups, "depth_"+strconv.Itoa(sw.Depth()), name)
+	}
+
+	return hosts, groups
+}
+
+func addToGroup(groups map[string][]string, group string, host string) {
+	group = strings.ToLower(notIdentifierRe.ReplaceAllString(group, "_"))
+	group = strings.Trim(group, "_")
+	groups[group] = append(groups[group], host)
+}
+
+func sortedGroups(groups map[string][]string) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func writeAnsibleYAML(w *bufio.Writer, hosts []ansibleHost, groups map[string][]string) {
+	w.WriteString("all:\n")
+	if len(hosts) == 0 {
+		return
+	}
+
+	w.WriteString("  hosts:\n")
+	for _, host := range hosts {
+		fmt.Fprintf(w, "    %s:\n      ansible_host: %s\n", yamlString(host.name), host.address)
+	}
+
+	if len(groups) == 0 {
+		return
+	}
+	w.WriteString("  children:\n")
+	for _, group := range sortedGroups(groups) {
+		fmt.Fprintf(w, "    %s:\n      hosts:\n", group)
+		for _, host := range groups[group] {
+			fmt.Fprintf(w, "        %s: {}\n", yamlString(host))
+		}
+	}
+}
+
+func writeAnsibleINI(w *bufio.Writer, hosts []ansibleHost, groups map[string][]string) {
+	for _, host := range hosts {
+		fmt.Fprintf(w, "%s ansible_host=%s\n", iniHost(host.name), host.address)
+	}
+
+	for _, group := range sortedGroups(groups) {
+		fmt.Fprintf(w, "\n[%s]\n", group)
+		for _, host := range groups[group] {
+			fmt.Fprintf(w, "%s\n", iniHost(host))
+		}
+	}
+}
+
+// yamlString quotes the string if it can not be written as a plain YAML scalar
+func yamlString(s string) string {
+	if plainYAMLRe.MatchString(s) {
+		return s
+	}
+
+	return strconv.Quote(s)
+}
+
+// ansibleHostName replaces the colons in the host name, Ansible takes the part after the colon for the port,
+// e.g. "spb_acc:3" or the IPv6 address
+func ansibleHostName(s string) string {
+	return strings.ReplaceAll(s, ":", "_")
+}
+
+// iniHost replaces the characters that separate the host name from its variables in the INI inventory
+func iniHost(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '=' || r == '#' || r == ';' || r == '[' || r == ']' {
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const utf8BOM = "\xEF\xBB\xBF"
+
+// CSVTable - table of the CSV export
+type CSVTable string
+
+const (
+	CSVSwitches CSVTable = "switches"
+	CSVLinks    CSVTable = "links"
+)
+
+// CSV renders the switches and the links of the network as flat tables
+type CSV struct {
+	delimiter rune
+	bom       bool
+	tables    []CSVTable
+}
+
+type CSVOption func(*CSV)
+
+// WithCSVDelimiter sets the field delimiter. For example, Excel with russian regional settings expects ';'
+func WithCSVDelimiter(delimiter rune) CSVOption {
+	return func(c *CSV) {
+		c.delimiter = delimiter
+	}
+}
+
+// WithCSVBOM writes the UTF-8 byte order mark, so that Excel recognizes the encoding
+func WithCSVBOM() CSVOption {
+	return func(c *CSV) {
+		c.bom = true
+	}
+}
+
+// WithCSVTables sets the tables to render. The tables are separated by an empty line.
+func WithCSVTables(tables ...CSVTable) CSVOption {
+	return func(c *CSV) {
+		c.tables = tables
+	}
+}
+
+func NewCSV(opts ...CSVOption) *CSV {
+	c := &CSV{
+		delimiter: ',',
+		tables:    []CSVTable{CSVSwitches, CSVLinks},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *CSV) Render(w io.Writer, network *domain.Network) error {
+	bw := bufio.NewWriter(w)
+	if c.bom {
+		bw.WriteString(utf8BOM)
+	}
+
+	for i, table := range c.tables {
+		if i > 0 {
+			bw.WriteString("\r\n")
+		}
+
+		var records [][]string
+		switch table {
+		case CSVSwitches:
+			records = switchRecords(network)
+		case CSVLinks:
+			records = linkRecords(network)
+		default:
+			return fmt.Errorf("csv render: unknown table %s", table)
+		}
+
+		writer := csv.NewWriter(bw)
+		writer.Comma = c.delimiter
+		writer.UseCRLF = true
+		if err := writer.WriteAll(records); err != nil {
+			return fmt.Errorf("csv render: %w", err)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("csv render: %w", err)
+	}
+
+	return nil
+}
+
+func switchRecords(network *domain.Network) [][]string {
+	records := [][]string{{"name", "ip", "platform", "version", "serial", "status", "depth"}}
+	for _, sw := range network.Switches() {
+		records = append(records, []string{
+			sw.Name(), sw.Address(), sw.Platform(), sw.Version(), sw.Serial(), string(sw.Status()), strconv.Itoa(sw.Depth()),
+		})
+	}
+
+	return records
+}
+
+func linkRecords(network *domain.Network) [][]string {
+	records := [][]string{{"switch_a", "ip_a", "port_a", "switch_b", "ip_b", "port_b", "stp_blocked"}}
+	for _, link := range network.Links() {
+		from, _ := network.Switch(link.FromAddress())
+		to, _ := network.Switch(link.ToAddress())
+		records = append(records, []string{
+			from.Name(), from.Address(), link.FromPort(), to.Name(), to.Address(), link.ToPort(),
+			strings.Join(network.BlockedInstances(link), ","),
+		})
+	}
+
+	return records
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+var notIdentifierRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// reservedIDs - keywords of the diagram languages that can not be used as identifiers of the nodes
+var reservedIDs = map[string]bool{
+	"end": true, "graph": true, "subgraph": true, "style": true, "class": true, "click": true, "default": true, "node": true,
+}
+
+// maxLabelInstances - the number of the spanning tree instances listed in the label of the link
+const maxLabelInstances = 3
+
+// Renderer writes the network in a particular format
+type Renderer interface {
+	Render(w io.Writer, network *domain.Network) error
+}
+
+// nodeIDs makes unique identifiers of the switches suitable for diagram languages
+func nodeIDs(network *domain.Network) map[string]string {
+	ids := make(map[string]string, network.Len())
+	used := make(map[string]bool, network.Len())
+
+	for _, sw := range network.Switches() {
+		name := sw.Name()
+		if name == "" {
+			name = sw.Address()
+		}
+
+		id := notIdentifierRe.ReplaceAllString(name, "_")
+		if id == "" || (id[0] >= '0' && id[0] <= '9') || reservedIDs[strings.ToLower(id)] {
+			id = "sw_" + id
+		}
+
+		uniqueID := id
+		for i := 2; used[uniqueID]; i++ {
+			uniqueID = id + "_" + strconv.Itoa(i)
+		}
+		used[uniqueID] = true
+		ids[sw.Address()] = uniqueID
+	}
+
+	return ids
+}
+
+// portsLabel describes the ports of the link. The label is empty if the ports are unknown.
+func portsLabel(link domain.Link) string {
+	if link.FromPort() == "" && link.ToPort() == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s - %s", portOrUnknown(link.FromPort()), portOrUnknown(link.ToPort()))
+}
+
+// blockedLabel describes the spanning tree instances in which the link is blocked. The label is empty if the link is not blocked.
+func blockedLabel(instances []string) string {
+	switch {
+	case len(instances) == 0:
+		return ""
+	case len(instances) > maxLabelInstances:
+		return fmt.Sprintf("blocked in %d instances", len(instances))
+	}
+
+	return "blocked " + strings.Join(instances, ", ")
+}
+
+func portOrUnknown(port string) string {
+	if port == "" {
+		return "?"
+	}
+
+	return port
+}
+
+// switchName returns the name of the switch or its address if the name is unknown
+func switchName(sw domain.Switch) string {
+	if sw.Name() == "" {
+		return sw.Address()
+	}
+
+	return sw.Name()
+}
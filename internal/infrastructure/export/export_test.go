@@ -0,0 +1,314 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/export"
+)
+
+func newTestNetwork() *domain.Network {
+	network := domain.NewNetwork()
+	names := map[string]string{
+		"10.0.0.1": "core-1.corp.local",
+		"10.0.0.2": "core-1.corp_local",
+		"10.0.0.3": "",
+		"10.0.0.4": "end",
+		"10.0.0.5": `sw "a"<b>`,
+	}
+	for _, address := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5"} {
+		sw, _ := domain.NewSwitch(address)
+		sw.SetName(names[address])
+		network.AddSwitch(*sw)
+	}
+
+	sw1, _ := network.Switch("10.0.0.1")
+	sw2, _ := network.Switch("10.0.0.2")
+	sw3, _ := network.Switch("10.0.0.3")
+	sw4, _ := network.Switch("10.0.0.4")
+	sw5, _ := network.Switch("10.0.0.5")
+	network.AddLink(sw1, sw2, domain.WithPorts("Te1/1/1", "Te1/1/2"))
+	network.AddLink(sw1, sw3)
+	network.AddLink(sw2, sw4, domain.WithPorts("Gi0/1", ""))
+	network.AddLink(sw4, sw5)
+
+	sw2.SetSTP([]domain.STPInstance{{Instance: "VLAN0010", BlockedPorts: []string{"GigabitEthernet0/1"}}})
+	network.UpdateSwitch(sw2)
+
+	return network
+}
+
+func TestMermaid_NodeIDs(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.NewMermaid().Render(&buf, newTestNetwork()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"10.0.0.1", "core_1_corp_local"},
+		{"10.0.0.2", "core_1_corp_local_2"},
+		{"10.0.0.3", "sw_10_0_0_3"},
+		{"10.0.0.4", "sw_end"},
+		{"10.0.0.5", "sw_a_b_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if want := "    " + tt.want + `["`; !strings.Contains(buf.String(), want) || !strings.Contains(buf.String(), "<br/>"+tt.address+`"]`) {
+				t.Errorf("Render() = %v, want the node %v of the switch %v", buf.String(), tt.want, tt.address)
+			}
+		})
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		name     string
+		renderer export.Renderer
+		contains []string
+	}{
+		{
+			"mermaid",
+			export.NewMermaid(),
+			[]string{
+				"graph LR\n",
+				`sw_a_b_["sw #quot;a#quot;#lt;b#gt;<br/>10.0.0.5"]`,
+				`core_1_corp_local ---|"Te1/1/1 - Te1/1/2"| core_1_corp_local_2`,
+				"core_1_corp_local --- sw_10_0_0_3\n",
+				`core_1_corp_local_2 -.-|"Gi0/1 - ?<br/>blocked VLAN0010"| sw_end`,
+			},
+		},
+		{
+			"plantuml",
+			export.NewPlantUML(),
+			[]string{
+				"@startuml\n",
+				`node "sw 'a'<b>\n10.0.0.5" as sw_a_b_`,
+				"core_1_corp_local -- core_1_corp_local_2 : Te1/1/1 - Te1/1/2\n",
+				"core_1_corp_local_2 .. sw_end : Gi0/1 - ?\\nblocked VLAN0010\n",
+				"@enduml\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.renderer.Render(&buf, newTestNetwork()); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Render() = %v, want to contain %v", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestXMLRenderers(t *testing.T) {
+	network := newTestNetwork()
+	sw, _ := domain.NewSwitch("10.0.0.6")
+	sw.SetName("sw<&>\"'\x01")
+	sw.SetPlatform("WS-C2960-24TT-L")
+	network.AddSwitch(*sw)
+
+	tests := []struct {
+		name     string
+		renderer export.Renderer
+		contains []string
+	}{
+		{
+			"graphml",
+			export.NewGraphML(),
+			[]string{
+				`<key id="depth" for="node" attr.name="depth" attr.type="int"></key>`,
+				`<data key="name">sw&lt;&amp;&gt;&#34;&#39;` + "�" + `</data>`,
+				`<edge id="e0" source="10.0.0.1" target="10.0.0.2">`,
+				`<data key="from_port">Te1/1/1</data>`,
+				`<data key="stp_blocked">VLAN0010</data>`,
+			},
+		},
+		{
+			"gexf",
+			export.NewGEXF(),
+			[]string{
+				`<attribute id="depth" title="depth" type="integer"></attribute>`,
+				`<node id="10.0.0.6" label="sw&lt;&amp;&gt;&#34;&#39;` + "�" + `">`,
+				`<attvalue for="platform" value="WS-C2960-24TT-L"></attvalue>`,
+				`<edge id="0" source="10.0.0.1" target="10.0.0.2">`,
+				`<attvalue for="stp_blocked" value="VLAN0010"></attvalue>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.renderer.Render(&buf, network); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("Render() produced invalid xml: %v", err)
+				}
+			}
+
+			for _, want := range tt.contains {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Render() = %v, want to contain %v", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTML(t *testing.T) {
+	network := newTestNetwork()
+	sw, _ := domain.NewSwitch("10.0.0.6")
+	sw.SetName("</script><script>alert(1)</script>")
+	network.AddSwitch(*sw)
+
+	var buf bytes.Buffer
+	if err := export.NewHTML(export.WithTitle("Branch <1>")).Render(&buf, network); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := buf.String()
+
+	if strings.Contains(got, "<script>alert(1)") {
+		t.Errorf("Render() does not escape the names of the switches")
+	}
+	if !strings.Contains(got, "<title>Branch &lt;1&gt;</title>") {
+		t.Errorf("Render() does not contain the escaped title")
+	}
+	if !strings.Contains(got, `"address":"10.0.0.1"`) {
+		t.Errorf("Render() does not contain the data of the network")
+	}
+	for _, external := range []string{`src="http`, `href="http`, "//cdn"} {
+		if strings.Contains(got, external) {
+			t.Errorf("Render() depends on external resource %s", external)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		csv    *export.CSV
+		expect string
+	}{
+		{
+			"switches with semicolon and bom",
+			export.NewCSV(export.WithCSVDelimiter(';'), export.WithCSVBOM(), export.WithCSVTables(export.CSVSwitches)),
+			"\ufeff" + "name;ip;platform;version;serial;status;depth\r\n" +
+				"core-1.corp.local;10.0.0.1;;;;not_crawled;0\r\n" +
+				"core-1.corp_local;10.0.0.2;;;;not_crawled;0\r\n" +
+				";10.0.0.3;;;;not_crawled;0\r\n" +
+				"end;10.0.0.4;;;;not_crawled;0\r\n" +
+				"\"sw \"\"a\"\"<b>\";10.0.0.5;;;;not_crawled;0\r\n",
+		},
+		{
+			"links",
+			export.NewCSV(export.WithCSVTables(export.CSVLinks)),
+			"switch_a,ip_a,port_a,switch_b,ip_b,port_b,stp_blocked\r\n" +
+				"core-1.corp.local,10.0.0.1,Te1/1/1,core-1.corp_local,10.0.0.2,Te1/1/2,\r\n" +
+				"core-1.corp.local,10.0.0.1,,,10.0.0.3,,\r\n" +
+				"core-1.corp_local,10.0.0.2,Gi0/1,end,10.0.0.4,,VLAN0010\r\n" +
+				"end,10.0.0.4,,\"sw \"\"a\"\"<b>\",10.0.0.5,,\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.csv.Render(&buf, newTestNetwork()); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if buf.String() != tt.expect {
+				t.Errorf("Render() = %q, want %q", buf.String(), tt.expect)
+			}
+		})
+	}
+}
+
+func TestAnsible(t *testing.T) {
+	network := domain.NewNetwork()
+	switches := []struct {
+		name     string
+		address  string
+		platform string
+		status   domain.Status
+		depth    int
+	}{
+		{"msk-core-1", "10.0.0.1", "WS-C3750X-48", domain.StatusCrawled, 0},
+		{"msk-acc-2", "10.0.0.2", "WS-C2960-24TT-L", domain.StatusCrawled, 1},
+		{"spb acc:3", "10.0.0.3", "", domain.StatusNotCrawled, 1},
+		{"msk-acc-4", "10.0.0.4", "WS-C2960-24TT-L", domain.StatusFailed, 1},
+		{"ext-1", "10.0.0.5", "", domain.StatusDiscarded, 1},
+	}
+	for _, s := range switches {
+		sw, _ := domain.NewSwitch(s.address)
+		sw.SetName(s.name)
+		sw.SetPlatform(s.platform)
+		sw.SetStatus(s.status)
+		sw.SetDepth(s.depth)
+		network.AddSwitch(*sw)
+	}
+
+	tests := []struct {
+		name   string
+		format export.AnsibleFormat
+		expect string
+	}{
+		{
+			"yaml",
+			export.AnsibleYAML,
+			"all:\n" +
+				"  hosts:\n" +
+				"    msk-core-1:\n      ansible_host: 10.0.0.1\n" +
+				"    msk-acc-2:\n      ansible_host: 10.0.0.2\n" +
+				"    \"spb acc_3\":\n      ansible_host: 10.0.0.3\n" +
+				"  children:\n" +
+				"    depth_0:\n      hosts:\n        msk-core-1: {}\n" +
+				"    depth_1:\n      hosts:\n        msk-acc-2: {}\n        \"spb acc_3\": {}\n" +
+				"    platform_ws_c2960_24tt_l:\n      hosts:\n        msk-acc-2: {}\n" +
+				"    platform_ws_c3750x_48:\n      hosts:\n        msk-core-1: {}\n" +
+				"    site_msk:\n      hosts:\n        msk-core-1: {}\n        msk-acc-2: {}\n",
+		},
+		{
+			"ini",
+			export.AnsibleINI,
+			"msk-core-1 ansible_host=10.0.0.1\n" +
+				"msk-acc-2 ansible_host=10.0.0.2\n" +
+				"spb_acc_3 ansible_host=10.0.0.3\n" +
+				"\n[depth_0]\nmsk-core-1\n" +
+				"\n[depth_1]\nmsk-acc-2\nspb_acc_3\n" +
+				"\n[platform_ws_c2960_24tt_l]\nmsk-acc-2\n" +
+				"\n[platform_ws_c3750x_48]\nmsk-core-1\n" +
+				"\n[site_msk]\nmsk-core-1\nmsk-acc-2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := export.NewAnsible(export.WithAnsibleFormat(tt.format)).Render(&buf, network); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if buf.String() != tt.expect {
+				t.Errorf("Render() = %q, want %q", buf.String(), tt.expect)
+			}
+		})
+	}
+}
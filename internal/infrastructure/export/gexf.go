@@ -0,0 +1,125 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// GEXF renders the network as a GEXF document (Gephi)
+type GEXF struct{}
+
+func NewGEXF() *GEXF {
+	return &GEXF{}
+}
+
+func (g *GEXF) Render(w io.Writer, network *domain.Network) error {
+	doc := gexfDocument{
+		Xmlns:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Graph: gexfGraph{
+			DefaultEdgeType: "undirected",
+			Mode:            "static",
+			Attributes: []gexfAttributes{
+				{
+					Class: "node",
+					Attributes: []gexfAttribute{
+						{ID: "address", Title: "address", Type: "string"},
+						{ID: "platform", Title: "platform", Type: "string"},
+						{ID: "version", Title: "version", Type: "string"},
+						{ID: "status", Title: "status", Type: "string"},
+						{ID: "depth", Title: "depth", Type: "integer"},
+					},
+				},
+				{
+					Class: "edge",
+					Attributes: []gexfAttribute{
+						{ID: "from_port", Title: "from_port", Type: "string"},
+						{ID: "to_port", Title: "to_port", Type: "string"},
+						{ID: "stp_blocked", Title: "stp_blocked", Type: "string"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, sw := range network.Switches() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:    sw.Address(),
+			Label: switchName(sw),
+			AttValues: []gexfAttValue{
+				{For: "address", Value: sw.Address()},
+				{For: "platform", Value: sw.Platform()},
+				{For: "version", Value: sw.Version()},
+				{For: "status", Value: string(sw.Status())},
+				{For: "depth", Value: strconv.Itoa(sw.Depth())},
+			},
+		})
+	}
+	for i, link := range network.Links() {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:     strconv.Itoa(i),
+			Source: link.FromAddress(),
+			Target: link.ToAddress(),
+			AttValues: []gexfAttValue{
+				{For: "from_port", Value: link.FromPort()},
+				{For: "to_port", Value: link.ToPort()},
+				{For: "stp_blocked", Value: strings.Join(network.BlockedInstances(link), ",")},
+			},
+		})
+	}
+
+	if err := writeXML(w, doc); err != nil {
+		return fmt.Errorf("gexf render: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// GraphML renders the network as a GraphML document (yEd, Gephi)
+type GraphML struct{}
+
+func NewGraphML() *GraphML {
+	return &GraphML{}
+}
+
+func (g *GraphML) Render(w io.Writer, network *domain.Network) error {
+	doc := graphMLDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "address", For: "node", AttrName: "address", AttrType: "string"},
+			{ID: "platform", For: "node", AttrName: "platform", AttrType: "string"},
+			{ID: "version", For: "node", AttrName: "version", AttrType: "string"},
+			{ID: "status", For: "node", AttrName: "status", AttrType: "string"},
+			{ID: "depth", For: "node", AttrName: "depth", AttrType: "int"},
+			{ID: "from_port", For: "edge", AttrName: "from_port", AttrType: "string"},
+			{ID: "to_port", For: "edge", AttrName: "to_port", AttrType: "string"},
+			{ID: "stp_blocked", For: "edge", AttrName: "stp_blocked", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "network", EdgeDefault: "undirected"},
+	}
+
+	for _, sw := range network.Switches() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: sw.Address(),
+			Data: []graphMLData{
+				{Key: "name", Value: switchName(sw)},
+				{Key: "address", Value: sw.Address()},
+				{Key: "platform", Value: sw.Platform()},
+				{Key: "version", Value: sw.Version()},
+				{Key: "status", Value: string(sw.Status())},
+				{Key: "depth", Value: strconv.Itoa(sw.Depth())},
+			},
+		})
+	}
+	for i, link := range network.Links() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     "e" + strconv.Itoa(i),
+			Source: link.FromAddress(),
+			Target: link.ToAddress(),
+			Data: []graphMLData{
+				{Key: "from_port", Value: link.FromPort()},
+				{Key: "to_port", Value: link.ToPort()},
+				{Key: "stp_blocked", Value: strings.Join(network.BlockedInstances(link), ",")},
+			},
+		})
+	}
+
+	if err := writeXML(w, doc); err != nil {
+		return fmt.Errorf("graphml render: %w", err)
+	}
+
+	return nil
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
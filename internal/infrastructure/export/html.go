@@ -0,0 +1,95 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+//go:embed viewer.html
+var viewerTemplate string
+
+var viewer = template.Must(template.New("viewer").Parse(viewerTemplate))
+
+type htmlNode struct {
+	Name     string        `json:"name"`
+	Address  string        `json:"address"`
+	Platform string        `json:"platform"`
+	Version  string        `json:"version"`
+	Status   domain.Status `json:"status"`
+	Depth    int           `json:"depth"`
+}
+
+type htmlLink struct {
+	Source   string   `json:"source"`
+	Target   string   `json:"target"`
+	FromPort string   `json:"from_port"`
+	ToPort   string   `json:"to_port"`
+	Blocked  []string `json:"blocked,omitempty"` //the spanning tree instances in which the link is blocked
+}
+
+type htmlData struct {
+	Nodes []htmlNode `json:"nodes"`
+	Links []htmlLink `json:"links"`
+}
+
+// HTML renders the network as a self-contained page with an interactive viewer that works offline
+type HTML struct {
+	title string
+}
+
+type HTMLOption func(*HTML)
+
+func WithTitle(title string) HTMLOption {
+	return func(h *HTML) {
+		h.title = title
+	}
+}
+
+func NewHTML(opts ...HTMLOption) *HTML {
+	h := &HTML{
+		title: "Network topology",
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *HTML) Render(w io.Writer, network *domain.Network) error {
+	data := htmlData{Nodes: []htmlNode{}, Links: []htmlLink{}}
+	for _, sw := range network.Switches() {
+		data.Nodes = append(data.Nodes, htmlNode{
+			Name:     sw.Name(),
+			Address:  sw.Address(),
+			Platform: sw.Platform(),
+			Version:  sw.Version(),
+			Status:   sw.Status(),
+			Depth:    sw.Depth(),
+		})
+	}
+	for _, link := range network.Links() {
+		data.Links = append(data.Links, htmlLink{
+			Source:   link.FromAddress(),
+			Target:   link.ToAddress(),
+			FromPort: link.FromPort(),
+			ToPort:   link.ToPort(),
+			Blocked:  network.BlockedInstances(link),
+		})
+	}
+
+	//html/template marshals the data to JSON and escapes it for the script context
+	if err := viewer.Execute(w, struct {
+		Title string
+		Data  htmlData
+	}{h.title, data}); err != nil {
+		return fmt.Errorf("html render: %w", err)
+	}
+
+	return nil
+}
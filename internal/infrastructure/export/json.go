@@ -0,0 +1,34 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// JSON renders the network as the JSON document of the crawl result
+type JSON struct {
+	pretty bool
+}
+
+func NewJSON(pretty bool) *JSON {
+	return &JSON{pretty: pretty}
+}
+
+func (j *JSON) Render(w io.Writer, network *domain.Network) error {
+	data := network.ToJSON()
+	if j.pretty {
+		var prettyJSON bytes.Buffer
+		json.Indent(&prettyJSON, data, "", "   ")
+		data = prettyJSON.Bytes()
+	}
+
+	if _, err := fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("json render: %w", err)
+	}
+
+	return nil
+}
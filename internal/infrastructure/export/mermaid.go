@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+// Mermaid renders the network as a Mermaid flowchart
+type Mermaid struct{}
+
+func NewMermaid() *Mermaid {
+	return &Mermaid{}
+}
+
+func (m *Mermaid) Render(w io.Writer, network *domain.Network) error {
+	ids := nodeIDs(network)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("graph LR\n")
+	for _, sw := range network.Switches() {
+		fmt.Fprintf(bw, "    %s[\"%s<br/>%s\"]\n", ids[sw.Address()], mermaidEscaper.Replace(switchName(sw)), sw.Address())
+	}
+	for _, link := range network.Links() {
+		from, to := ids[link.FromAddress()], ids[link.ToAddress()]
+
+		line := "---"
+		label := mermaidEscaper.Replace(portsLabel(link))
+		if blocked := blockedLabel(network.BlockedInstances(link)); blocked != "" {
+			line = "-.-" //the link blocked by the spanning tree is dotted
+			label = strings.TrimPrefix(label+"<br/>"+blocked, "<br/>")
+		}
+
+		if label != "" {
+			fmt.Fprintf(bw, "    %s %s|\"%s\"| %s\n", from, line, label, to)
+		} else {
+			fmt.Fprintf(bw, "    %s %s %s\n", from, line, to)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("mermaid render: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+var plantUMLEscaper = strings.NewReplacer(`"`, "'", `\`, `\\`)
+
+// PlantUML renders the network as a PlantUML deployment diagram
+type PlantUML struct{}
+
+func NewPlantUML() *PlantUML {
+	return &PlantUML{}
+}
+
+func (p *PlantUML) Render(w io.Writer, network *domain.Network) error {
+	ids := nodeIDs(network)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("@startuml\n")
+	for _, sw := range network.Switches() {
+		fmt.Fprintf(bw, "node \"%s\\n%s\" as %s\n", plantUMLEscaper.Replace(switchName(sw)), sw.Address(), ids[sw.Address()])
+	}
+	for _, link := range network.Links() {
+		from, to := ids[link.FromAddress()], ids[link.ToAddress()]
+
+		line := "--"
+		label := plantUMLEscaper.Replace(portsLabel(link))
+		if blocked := blockedLabel(network.BlockedInstances(link)); blocked != "" {
+			line = ".." //the link blocked by the spanning tree is dotted
+			label = strings.TrimPrefix(label+"\\n"+blocked, "\\n")
+		}
+
+		if label != "" {
+			fmt.Fprintf(bw, "%s %s %s : %s\n", from, line, to, label)
+		} else {
+			fmt.Fprintf(bw, "%s %s %s\n", from, line, to)
+		}
+	}
+	bw.WriteString("@enduml\n")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("plantuml render: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package jump opens the connections to the switches through the SSH jump hosts and the SOCKS5 proxies
+package jump
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+const (
+	SchemeSSH    = "ssh"
+	SchemeSOCKS5 = "socks5"
+)
+
+const (
+	defaultSSHPort    = "22"
+	defaultSOCKS5Port = "1080"
+	dialTimeout       = 15 * time.Second
+)
+
+var (
+	ErrUnknownScheme = errors.New("unknown scheme of the jump host, use ssh or socks5")
+	ErrNoAuth        = errors.New("no authentication method for the jump host")
+)
+
+// Dialer opens the connections, e.g. *net.Dialer, the SSH jump host or the SOCKS5 proxy
+type Dialer interface {
+	Dial(network string, address string) (net.Conn, error)
+}
+
+// Auth - credentials of the jump host, separate from the credentials of the switches
+type Auth struct {
+	User       string //used if the url of the jump host has no user
+	Password   string //the password of the SSH user or the SOCKS5 proxy
+	KeyFile    string //the private key of the SSH user
+	Agent      bool   //authenticate with the keys of ssh-agent (SSH_AUTH_SOCK)
+	KnownHosts string //the known_hosts file checking the key of the SSH jump host
+}
+
+// Direct returns the dialer connecting without the jump host
+func Direct() Dialer {
+	return &net.Dialer{Timeout: dialTimeout}
+}
+
+// Parse returns the dialer of the jump host given by the url: ssh://user@host[:port] or socks5://[user@]host[:port].
+// The password in the url takes precedence over the password of the auth.
+func Parse(rawURL string, auth Auth) (Dialer, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("jump parse [%s]: %w", rawURL, err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("jump parse [%s]: empty host", rawURL)
+	}
+	if u.User != nil {
+		auth.User = u.User.Username()
+		if password, ok := u.User.Password(); ok {
+			auth.Password = password
+		}
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case SchemeSSH:
+		config, agentKeys, err := clientConfig(auth)
+		if err != nil {
+			return nil, fmt.Errorf("jump parse [%s]: %w", u.Redacted(), err)
+		}
+		jumpHost := NewSSH(hostPort(u, defaultSSHPort), config)
+		jumpHost.agent = agentKeys
+		return jumpHost, nil
+	case SchemeSOCKS5:
+		var proxyAuth *proxy.Auth
+		if auth.User != "" {
+			proxyAuth = &proxy.Auth{User: auth.User, Password: auth.Password}
+		}
+		dialer, err := proxy.SOCKS5("tcp", hostPort(u, defaultSOCKS5Port), proxyAuth, Direct().(*net.Dialer))
+		if err != nil {
+			return nil, fmt.Errorf("jump parse [%s]: %w", u.Redacted(), err)
+		}
+		return dialer, nil
+	}
+
+	return nil, fmt.Errorf("jump parse [%s]: %w", u.Redacted(), ErrUnknownScheme)
+}
+
+// hostPort returns the address of the jump host with the default port if the url has no port
+func hostPort(u *url.URL, defaultPort string) string {
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+
+	return net.JoinHostPort(u.Hostname(), defaultPort)
+}
+
+// IPFilter - the addresses of the switches reached through the dialer
+type IPFilter interface {
+	Allow(ip net.IP) bool
+}
+
+// Router selects the dialer of the switch by its address: the first added dialer whose filter allows the address
+// is used, otherwise the default one.
+type Router struct {
+	fallback Dialer
+	routes   []route
+}
+
+type route struct {
+	filter IPFilter
+	dialer Dialer
+}
+
+func NewRouter(fallback Dialer) *Router {
+	return &Router{fallback: fallback}
+}
+
+// Add registers the dialer for the switches allowed by the filter, e.g. for the subnets behind the jump host
+func (r *Router) Add(filter IPFilter, dialer Dialer) {
+	r.routes = append(r.routes, route{filter: filter, dialer: dialer})
+}
+
+// Dial connects to the address with the dialer of the address
+func (r *Router) Dial(network string, address string) (net.Conn, error) {
+	return r.Dialer(address).Dial(network, address)
+}
+
+// Dialer returns the dialer of the address given as host:port
+func (r *Router) Dialer(address string) Dialer {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	ip := net.ParseIP(host)
+	for _, route := range r.routes {
+		if route.filter.Allow(ip) {
+			return route.dialer
+		}
+	}
+
+	return r.fallback
+}
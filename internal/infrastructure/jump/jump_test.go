@@ -0,0 +1,342 @@
+package jump
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/pkg/ip"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestParse(t *testing.T) {
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHosts, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		auth    Auth
+		wantErr error
+	}{
+		{"ssh", "ssh://admin@bastion.corp.local", Auth{Password: "secret", KnownHosts: knownHosts}, nil},
+		{"ssh user of auth", "ssh://bastion.corp.local:2222", Auth{User: "admin", Password: "secret", KnownHosts: knownHosts}, nil},
+		{"ssh without auth", "ssh://admin@bastion.corp.local", Auth{KnownHosts: knownHosts}, ErrNoAuth},
+		{"socks5", "socks5://proxy.corp.local:1080", Auth{}, nil},
+		{"socks5 with password", "socks5://admin:secret@[2001:db8::1]", Auth{}, nil},
+		{"unknown scheme", "http://proxy.corp.local:3128", Auth{}, ErrUnknownScheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.url, tt.auth)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Parse("ssh://admin@bastion.corp.local", Auth{Password: "secret", KnownHosts: filepath.Join(t.TempDir(), "none")}); err == nil {
+		t.Errorf("Parse() without known_hosts error = nil, want error")
+	}
+}
+
+// namedDialer - dialer recognized by the name in the tests of the router
+type namedDialer string
+
+func (d namedDialer) Dial(string, string) (net.Conn, error) {
+	return nil, errors.New(string(d))
+}
+
+func TestRouter_Dialer(t *testing.T) {
+	bastion := ip.NewFilter()
+	bastion.Add("10.1.0.0/16")
+	bastion.Add("2001:db8:1::/48")
+	proxy := ip.NewFilter()
+	proxy.Add("10.0.0.0/8")
+
+	router := NewRouter(namedDialer("direct"))
+	router.Add(bastion, namedDialer("ssh"))
+	router.Add(proxy, namedDialer("socks5"))
+
+	tests := []struct {
+		address string
+		want    namedDialer
+	}{
+		{"10.1.2.3:23", "ssh"},
+		{"[2001:db8:1::5]:23", "ssh"},
+		{"10.2.0.1:23", "socks5"},
+		{"192.168.1.1:23", "direct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := router.Dialer(tt.address); got != tt.want {
+				t.Errorf("Router.Dialer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// startEcho starts the server returning the received data, e.g. the telnet server of the switch
+func startEcho(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// startBastion starts the SSH server forwarding the connections (direct-tcpip) and returns its address,
+// the known_hosts file with its key and the channel closing the connections of the clients
+func startBastion(t *testing.T, password string, keys ...ssh.PublicKey) (string, string, chan struct{}) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(meta ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if meta.User() == "jump" && string(pass) == password {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+		PublicKeyCallback: func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			for _, allowed := range keys {
+				if meta.User() == "jump" && bytes.Equal(key.Marshal(), allowed.Marshal()) {
+					return nil, nil
+				}
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	drop := make(chan struct{}, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveBastion(conn, config, drop)
+		}
+	}()
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(listener.Addr().String())}, signer.PublicKey())
+	if err := os.WriteFile(knownHosts, []byte(line+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return listener.Addr().String(), knownHosts, drop
+}
+
+func serveBastion(conn net.Conn, config *ssh.ServerConfig, drop <-chan struct{}) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		<-drop
+		serverConn.Close()
+	}()
+
+	for newChannel := range channels {
+		var target struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &target) != nil {
+			newChannel.Reject(ssh.UnknownChannelType, "only direct-tcpip")
+			continue
+		}
+		remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			remote.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+		go func() {
+			io.Copy(channel, remote)
+			channel.Close()
+		}()
+		go func() {
+			io.Copy(remote, channel)
+			remote.Close()
+		}()
+	}
+}
+
+// echo sends the text through the connection and returns the answer
+func echo(t *testing.T, conn net.Conn, text string) string {
+	t.Helper()
+
+	if _, err := conn.Write([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+	answer := make([]byte, len(text))
+	if _, err := io.ReadFull(conn, answer); err != nil {
+		t.Fatal(err)
+	}
+
+	return string(answer)
+}
+
+func TestSSH_Dial(t *testing.T) {
+	target := startEcho(t)
+	bastion, knownHosts, drop := startBastion(t, "secret")
+
+	dialer, err := Parse("ssh://jump@"+bastion, Auth{Password: "secret", KnownHosts: knownHosts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.(*SSH).Close()
+
+	conn, err := dialer.Dial("tcp", target)
+	if err != nil {
+		t.Fatalf("SSH.Dial() error = %v", err)
+	}
+	if got := echo(t, conn, "show version\r\n"); got != "show version\r\n" {
+		t.Errorf("echo = %q", got)
+	}
+	conn.Close()
+
+	//the jump host could not reach the switch
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+	var openErr *ssh.OpenChannelError
+	if _, err := dialer.Dial("tcp", closed.Addr().String()); !errors.As(err, &openErr) {
+		t.Errorf("SSH.Dial() to the closed port error = %v, want %T", err, openErr)
+	}
+
+	//the connection to the jump host is lost and reopened
+	drop <- struct{}{}
+	conn, err = dialer.Dial("tcp", target)
+	if err != nil {
+		t.Fatalf("SSH.Dial() after the lost connection error = %v", err)
+	}
+	if got := echo(t, conn, "exit\r\n"); got != "exit\r\n" {
+		t.Errorf("echo = %q", got)
+	}
+	conn.Close()
+}
+
+func TestSSH_DialWrongPassword(t *testing.T) {
+	bastion, knownHosts, _ := startBastion(t, "secret")
+
+	dialer, err := Parse("ssh://jump@"+bastion, Auth{Password: "wrong", KnownHosts: knownHosts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dialer.Dial("tcp", startEcho(t)); err == nil {
+		t.Errorf("SSH.Dial() error = nil, want the authentication error")
+	}
+}
+
+func TestSSH_DialAgent(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets: %v", err)
+	}
+	defer listener.Close()
+	closed := make(chan struct{}, 1) //the connection to the agent is closed by the client
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				agent.ServeAgent(keyring, conn)
+				closed <- struct{}{}
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	bastion, knownHosts, _ := startBastion(t, "secret", signer.PublicKey())
+	dialer, err := Parse("ssh://jump@"+bastion, Auth{Agent: true, KnownHosts: knownHosts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.(*SSH).Close()
+
+	conn, err := dialer.Dial("tcp", startEcho(t))
+	if err != nil {
+		t.Fatalf("SSH.Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Errorf("the connection to ssh-agent is not closed after the handshake")
+	}
+	if got := echo(t, conn, "show clock\r\n"); got != "show clock\r\n" {
+		t.Errorf("echo = %q", got)
+	}
+}
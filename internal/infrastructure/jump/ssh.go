@@ -0,0 +1,164 @@
+package jump
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSH - the jump host forwarding the connections to the switches (ssh -J). The connection to the jump host
+// is opened on the first dial and shared by all the connections to the switches.
+type SSH struct {
+	address string
+	config  *ssh.ClientConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+	agent  *agentAuth //nil without ssh-agent
+}
+
+func NewSSH(address string, config *ssh.ClientConfig) *SSH {
+	return &SSH{address: address, config: config}
+}
+
+// Dial connects to the address through the jump host. If the connection to the jump host is lost,
+// it is reopened once.
+func (s *SSH) Dial(network string, address string) (net.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if s.client == nil {
+			client, err := ssh.Dial("tcp", s.address, s.config)
+			s.agent.close() //the keys of the agent are needed only for the handshake
+			if err != nil {
+				return nil, fmt.Errorf("jump dial [%s]: %w", s.address, err)
+			}
+			s.client = client
+		}
+
+		conn, err := s.client.Dial(network, address)
+		var openErr *ssh.OpenChannelError
+		if err == nil {
+			return conn, nil
+		} else if errors.As(err, &openErr) || attempt > 0 { //the jump host could not reach the switch
+			return nil, fmt.Errorf("jump dial [%s] %s: %w", s.address, address, err)
+		}
+
+		s.client.Close()
+		s.client = nil
+	}
+}
+
+// Close closes the connection to the jump host
+func (s *SSH) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+
+	return err
+}
+
+// clientConfig returns the configuration of the SSH client authenticating with the key, ssh-agent and the password
+// in this order, and the keys of ssh-agent if they are used. The key of the jump host must be in the known_hosts file,
+// ~/.ssh/known_hosts by default.
+func clientConfig(auth Auth) (*ssh.ClientConfig, *agentAuth, error) {
+	if auth.User == "" {
+		return nil, nil, errors.New("the user of the jump host is not set")
+	}
+
+	var methods []ssh.AuthMethod
+	if auth.KeyFile != "" {
+		key, err := os.ReadFile(auth.KeyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("private key %s: %w", auth.KeyFile, err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	var agentKeys *agentAuth
+	if socket := os.Getenv("SSH_AUTH_SOCK"); auth.Agent && socket != "" {
+		agentKeys = &agentAuth{socket: socket}
+		methods = append(methods, ssh.PublicKeysCallback(agentKeys.signers))
+	}
+	if auth.Password != "" {
+		methods = append(methods, ssh.Password(auth.Password))
+	}
+	if len(methods) == 0 {
+		return nil, nil, ErrNoAuth
+	}
+
+	knownHosts := auth.KnownHosts
+	if knownHosts == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil, err
+		}
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("known hosts: %w", err)
+	}
+
+	return &ssh.ClientConfig{
+		User:            auth.User,
+		Auth:            methods,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}, agentKeys, nil
+}
+
+// agentAuth authenticates with the keys of ssh-agent. The connection to the agent is opened on every handshake
+// with the jump host and closed after it.
+type agentAuth struct {
+	socket string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (a *agentAuth) signers() ([]ssh.Signer, error) {
+	conn, err := net.Dial("unix", a.socket)
+	if err != nil {
+		return nil, fmt.Errorf("ssh-agent: %w", err)
+	}
+
+	a.mu.Lock()
+	if a.conn != nil {
+		a.conn.Close()
+	}
+	a.conn = conn
+	a.mu.Unlock()
+
+	return agent.NewClient(conn).Signers()
+}
+
+// close closes the connection to the agent. The agent may be nil.
+func (a *agentAuth) close() {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.conn != nil {
+		a.conn.Close()
+		a.conn = nil
+	}
+}
@@ -0,0 +1,217 @@
+// Package profile reads the connection parameters of the switches from the config file
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/pkg/ip"
+)
+
+const (
+	TransportTelnet = "telnet"
+	TransportSNMP   = "snmp"
+)
+
+var (
+	ErrInvalidProfile = errors.New("wrong connection profile")
+	ErrEmptyMatch     = errors.New("the profile matches no switches")
+)
+
+// Duration - time.Duration written in the config as "30s", "1m30s"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+
+	return nil
+}
+
+// Profile - connection parameters of the switches. The zero fields are taken from the default profile,
+// otherwise from the flags of the application.
+type Profile struct {
+	Name        string   `json:"name,omitempty"`
+	Match       []string `json:"match,omitempty"` //ip addresses, subnets and patterns of the names, e.g. "ts-*.corp.local"
+	Port        int      `json:"port,omitempty"`
+	Transport   string   `json:"transport,omitempty"`    //telnet or snmp
+	Timeout     Duration `json:"timeout,omitempty"`      //the time of waiting for the output of the switch
+	WritePacing Duration `json:"write_pacing,omitempty"` //the pause after every write to the switch
+	User        string   `json:"user,omitempty"`
+	Password    string   `json:"password,omitempty"`
+
+	filter   *ip.Filter
+	patterns []string
+}
+
+// Config - the connection profiles of the switches. The first profile matching the switch is merged
+// with the default one.
+type Config struct {
+	Default  Profile   `json:"default"`
+	Profiles []Profile `json:"profiles"`
+}
+
+// Load reads the config of the profiles from the JSON file
+func Load(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("profile load [%s]: %w", filename, err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("profile load [%s]: %w", filename, err)
+	}
+	if err := config.compile(); err != nil {
+		return nil, fmt.Errorf("profile load [%s]: %w", filename, err)
+	}
+
+	return &config, nil
+}
+
+// compile checks the profiles and prepares their rules
+func (c *Config) compile() error {
+	if err := c.Default.check(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+
+	for i := range c.Profiles {
+		p := &c.Profiles[i]
+		if p.Name == "" {
+			p.Name = fmt.Sprintf("#%d", i+1)
+		}
+		if err := p.check(); err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		if len(p.Match) == 0 {
+			return fmt.Errorf("%s: %w", p.Name, ErrEmptyMatch)
+		}
+
+		p.filter = ip.NewFilter()
+		for _, rule := range p.Match {
+			rule = strings.TrimSpace(rule)
+			if p.filter.Add(rule) == nil {
+				continue
+			}
+			if _, err := path.Match(rule, ""); err != nil || rule == "" {
+				return fmt.Errorf("%s: %w: wrong rule %q", p.Name, ErrInvalidProfile, rule)
+			}
+			p.patterns = append(p.patterns, strings.ToLower(rule))
+		}
+	}
+
+	return nil
+}
+
+func (p Profile) check() error {
+	if p.Port < 0 || p.Port > 65535 {
+		return fmt.Errorf("%w: port %d", ErrInvalidProfile, p.Port)
+	}
+	switch p.Transport {
+	case "", TransportTelnet, TransportSNMP:
+	default:
+		return fmt.Errorf("%w: unknown transport %s, use telnet or snmp", ErrInvalidProfile, p.Transport)
+	}
+	if p.Timeout < 0 || p.WritePacing < 0 {
+		return fmt.Errorf("%w: negative duration", ErrInvalidProfile)
+	}
+
+	return nil
+}
+
+// matches reports whether the rules of the profile match the address or one of the names of the switch
+func (p Profile) matches(address string, names []string) bool {
+	if p.filter != nil && p.filter.Allow(net.ParseIP(address)) {
+		return true
+	}
+	for _, pattern := range p.patterns {
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, strings.ToLower(name)); ok && name != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Resolve returns the effective profile of the switch: the first profile matching its address or names
+// merged with the default profile. The index of the profile is -1 if only the default profile is applied.
+func (c *Config) Resolve(address string, names ...string) (Profile, int) {
+	for i, p := range c.Profiles {
+		if p.matches(address, names) {
+			return c.Default.merge(p), i
+		}
+	}
+
+	return c.Default.merge(Profile{Name: "default"}), -1
+}
+
+// merge returns the profile with the fields of the other profile set over the fields of this one
+func (p Profile) merge(other Profile) Profile {
+	result := p
+	result.Name = other.Name
+	result.Match = other.Match
+	if other.Port != 0 {
+		result.Port = other.Port
+	}
+	if other.Transport != "" {
+		result.Transport = other.Transport
+	}
+	if other.Timeout != 0 {
+		result.Timeout = other.Timeout
+	}
+	if other.WritePacing != 0 {
+		result.WritePacing = other.WritePacing
+	}
+	if other.User != "" {
+		result.User = other.User
+	}
+	if other.Password != "" { //the user without the password keeps the password of the default profile
+		result.Password = other.Password
+	}
+
+	return result
+}
+
+// String describes the profile without the password, e.g. for the verbose mode
+func (p Profile) String() string {
+	var fields []string
+	if p.Transport != "" {
+		fields = append(fields, "transport: "+p.Transport)
+	}
+	if p.Port != 0 {
+		fields = append(fields, fmt.Sprintf("port: %d", p.Port))
+	}
+	if p.Timeout != 0 {
+		fields = append(fields, "timeout: "+time.Duration(p.Timeout).String())
+	}
+	if p.WritePacing != 0 {
+		fields = append(fields, "write pacing: "+time.Duration(p.WritePacing).String())
+	}
+	if p.User != "" {
+		fields = append(fields, "user: "+p.User)
+	}
+	if p.Password != "" {
+		fields = append(fields, "password: ***")
+	}
+
+	return fmt.Sprintf("profile %s {%s}", p.Name, strings.Join(fields, ", "))
+}
@@ -0,0 +1,96 @@
+package profile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/profile"
+)
+
+const config = `{
+  "default": {"timeout": "30s", "write_pacing": "200ms", "password": "default-secret"},
+  "profiles": [
+    {"name": "terminal servers", "match": ["ts-*.corp.local", "10.20.0.0/16"], "port": 2001, "write_pacing": "50ms", "user": "console", "password": "secret"},
+    {"name": "snmp", "match": ["10.30.0.1", "2001:db8:30::/48"], "transport": "snmp"},
+    {"match": ["CORE*"], "timeout": "2m"},
+    {"name": "user only", "match": ["10.40.0.0/16"], "user": "netops"}
+  ]
+}`
+
+func writeConfig(t *testing.T, text string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(filename, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return filename
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	config, err := profile.Load(writeConfig(t, config))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		names     []string
+		wantIndex int
+		want      string
+	}{
+		{"subnet", "10.20.1.1", nil, 0, "profile terminal servers {port: 2001, timeout: 30s, write pacing: 50ms, user: console, password: ***}"},
+		{"host name", "10.0.0.1", []string{"", "TS-01.corp.local"}, 0, "profile terminal servers {port: 2001, timeout: 30s, write pacing: 50ms, user: console, password: ***}"},
+		{"address", "10.30.0.1", []string{"core1"}, 1, "profile snmp {transport: snmp, timeout: 30s, write pacing: 200ms, password: ***}"},
+		{"ipv6", "2001:db8:30::5", nil, 1, "profile snmp {transport: snmp, timeout: 30s, write pacing: 200ms, password: ***}"},
+		{"unnamed", "10.0.0.2", []string{"core2"}, 2, "profile #3 {timeout: 2m0s, write pacing: 200ms, password: ***}"},
+		{"user only", "10.40.0.1", nil, 3, "profile user only {timeout: 30s, write pacing: 200ms, user: netops, password: ***}"},
+		{"default", "10.0.0.3", []string{"acc3"}, -1, "profile default {timeout: 30s, write pacing: 200ms, password: ***}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, index := config.Resolve(tt.address, tt.names...)
+			if index != tt.wantIndex || got.String() != tt.want {
+				t.Errorf("Config.Resolve() = %v, %d, want %v, %d", got, index, tt.want, tt.wantIndex)
+			}
+		})
+	}
+
+	if got, _ := config.Resolve("10.20.1.1"); time.Duration(got.WritePacing) != 50*time.Millisecond || got.Password != "secret" {
+		t.Errorf("Config.Resolve() = %+v, want the write pacing and the password of the profile", got)
+	}
+	if got, _ := config.Resolve("10.40.0.1"); got.User != "netops" || got.Password != "default-secret" {
+		t.Errorf("Config.Resolve() = %+v, want the user of the profile and the default password", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+	}{
+		{"wrong port", `{"profiles": [{"match": ["10.0.0.1"], "port": 70000}]}`, profile.ErrInvalidProfile},
+		{"wrong transport", `{"default": {"transport": "ssh"}}`, profile.ErrInvalidProfile},
+		{"wrong pattern", `{"profiles": [{"match": ["ts-[.corp.local"]}]}`, profile.ErrInvalidProfile},
+		{"no match", `{"profiles": [{"port": 2001}]}`, profile.ErrEmptyMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := profile.Load(writeConfig(t, tt.text)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Load() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := profile.Load(writeConfig(t, `{"default": {"timeout": "soon"}}`)); err == nil {
+		t.Errorf("Load() with the wrong duration error = nil, want error")
+	}
+}
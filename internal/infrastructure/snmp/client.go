@@ -0,0 +1,496 @@
+package snmp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const defaultPort = 161
+
+var ErrRequestFailed = errors.New("the agent returned an error")
+
+const (
+	oidSysDescr = ".1.3.6.1.2.1.1.1.0"
+	oidSysName  = ".1.3.6.1.2.1.1.5.0"
+	oidIfDescr  = ".1.3.6.1.2.1.2.2.1.2"
+
+	//CISCO-CDP-MIB::cdpCacheEntry, index: ifIndex.deviceIndex
+	oidCDPCacheEntry = ".1.3.6.1.4.1.9.9.23.1.2.1.1"
+	cdpAddressType   = 3
+	cdpAddress       = 4
+	cdpVersion       = 5
+	cdpDeviceID      = 6
+	cdpDevicePort    = 7
+	cdpPlatform      = 8
+	cdpNativeVLAN    = 11
+
+	//LLDP-MIB::lldpRemEntry, index: timeMark.localPortNum.index
+	oidLLDPRemEntry = ".1.0.8802.1.1.2.1.4.1.1"
+	lldpRemPortID   = 7
+	lldpRemSysName  = 9
+	lldpRemSysDesc  = 10
+
+	//LLDP-MIB::lldpRemManAddrEntry, index: timeMark.localPortNum.index.addrSubtype.addrLen.addr
+	oidLLDPRemManAddrEntry = ".1.0.8802.1.1.2.1.4.2.1"
+
+	//LLDP-MIB::lldpLocPortId, index: localPortNum
+	oidLLDPLocPortID = ".1.0.8802.1.1.2.1.3.7.1.3"
+)
+
+const (
+	addressTypeIP      = 1  //cdpCacheAddressType and the IANA address family of LLDP for IPv4
+	addressTypeIPv6    = 2  //the IANA address family of LLDP for IPv6
+	cdpAddressTypeIPv6 = 20 //cdpCacheAddressType for IPv6
+)
+
+var versionRe = regexp.MustCompile(`Version ([^,\s]+)`)
+
+// Version - version of the SNMP protocol
+type Version string
+
+const (
+	Version2c Version = "2c"
+	Version3  Version = "3"
+)
+
+// Client discovers the neighbors of the switch over SNMP by the CDP and LLDP tables.
+// The user name and the password of Connect are not used: the SNMP credentials are set by the options.
+type Client struct {
+	version   Version
+	community string
+	port      uint16
+	timeout   time.Duration
+	retries   int
+	usm       *gosnmp.UsmSecurityParameters
+	msgFlags  gosnmp.SnmpV3MsgFlags
+
+	snmp *gosnmp.GoSNMP
+	info domain.DeviceReport
+}
+
+type Option func(*Client)
+
+// WithCommunity sets the community of SNMP v2c
+func WithCommunity(community string) Option {
+	return func(c *Client) {
+		c.version = Version2c
+		c.community = community
+	}
+}
+
+// WithV3 switches the client to SNMP v3 with the user security model. The empty passwords disable
+// the authentication and the privacy respectively. The empty protocols mean SHA and AES.
+func WithV3(user string, authProtocol string, authPassword string, privProtocol string, privPassword string) Option {
+	return func(c *Client) {
+		if authProtocol == "" {
+			authProtocol = "SHA"
+		}
+		if privProtocol == "" {
+			privProtocol = "AES"
+		}
+
+		c.version = Version3
+		c.usm = &gosnmp.UsmSecurityParameters{
+			UserName:                 user,
+			AuthenticationProtocol:   authProtocols[strings.ToUpper(authProtocol)],
+			AuthenticationPassphrase: authPassword,
+			PrivacyProtocol:          privProtocols[strings.ToUpper(privProtocol)],
+			PrivacyPassphrase:        privPassword,
+		}
+
+		c.msgFlags = gosnmp.NoAuthNoPriv
+		if authPassword != "" {
+			c.msgFlags = gosnmp.AuthNoPriv
+			if privPassword != "" {
+				c.msgFlags = gosnmp.AuthPriv
+			}
+		} else {
+			c.usm.AuthenticationProtocol = gosnmp.NoAuth
+		}
+		if c.msgFlags != gosnmp.AuthPriv {
+			c.usm.PrivacyProtocol = gosnmp.NoPriv
+		}
+	}
+}
+
+func WithPort(port uint16) Option {
+	return func(c *Client) {
+		c.port = port
+	}
+}
+
+// WithTimeout sets the timeout of one request and the number of retries
+func WithTimeout(timeout time.Duration, retries int) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+		c.retries = retries
+	}
+}
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"MD5": gosnmp.MD5, "SHA": gosnmp.SHA, "SHA224": gosnmp.SHA224, "SHA256": gosnmp.SHA256, "SHA384": gosnmp.SHA384, "SHA512": gosnmp.SHA512,
+}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"DES": gosnmp.DES, "AES": gosnmp.AES, "AES192": gosnmp.AES192, "AES256": gosnmp.AES256, "AES192C": gosnmp.AES192C, "AES256C": gosnmp.AES256C,
+}
+
+// CheckProtocols returns an error if the authentication or the privacy protocol of SNMP v3 is unknown
+func CheckProtocols(authProtocol string, privProtocol string) error {
+	if _, ok := authProtocols[strings.ToUpper(authProtocol)]; !ok && authProtocol != "" {
+		return fmt.Errorf("snmp: unknown authentication protocol %s", authProtocol)
+	}
+	if _, ok := privProtocols[strings.ToUpper(privProtocol)]; !ok && privProtocol != "" {
+		return fmt.Errorf("snmp: unknown privacy protocol %s", privProtocol)
+	}
+
+	return nil
+}
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		version:   Version2c,
+		community: "public",
+		port:      defaultPort,
+		timeout:   2 * time.Second,
+		retries:   2,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Connect checks that the agent of the switch answers and takes the name and the version of the switch from sysName and sysDescr
+func (c *Client) Connect(address string, user string, password string) error {
+	c.info = domain.DeviceReport{}
+
+	if c.snmp != nil {
+		c.Close()
+	}
+
+	if net.ParseIP(address) == nil {
+		return fmt.Errorf("snmp connect [%v]: wrong ip address", address)
+	}
+	c.info.Address = address
+
+	c.snmp = &gosnmp.GoSNMP{
+		Target:         address,
+		Port:           c.port,
+		Community:      c.community,
+		Version:        gosnmp.Version2c,
+		Timeout:        c.timeout,
+		Retries:        c.retries,
+		MaxOids:        gosnmp.MaxOids,
+		MaxRepetitions: 25,
+	}
+	if c.version == Version3 {
+		c.snmp.Version = gosnmp.Version3
+		c.snmp.SecurityModel = gosnmp.UserSecurityModel
+		c.snmp.MsgFlags = c.msgFlags
+		c.snmp.SecurityParameters = c.usm.Copy()
+	}
+
+	if err := c.snmp.Connect(); err != nil {
+		c.snmp = nil
+		return fmt.Errorf("snmp connect [%v]: %w", address, err)
+	}
+
+	result, err := c.snmp.Get([]string{oidSysName, oidSysDescr})
+	if err == nil && result.Error != gosnmp.NoError {
+		err = fmt.Errorf("%w: %s", ErrRequestFailed, result.Error)
+	}
+	if err != nil {
+		c.Close()
+		return fmt.Errorf("snmp connect [%v]: %w", address, err)
+	}
+	for _, variable := range result.Variables {
+		switch variable.Name {
+		case oidSysName:
+			c.info.Name = stringValue(variable)
+		case oidSysDescr:
+			c.info.Version = parseVersion(stringValue(variable))
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) Close() error {
+	if c.snmp == nil {
+		return fmt.Errorf("snmp close [%v]: connection already closed", c.info.Address)
+	}
+
+	err := c.snmp.Conn.Close()
+	c.snmp = nil
+
+	return err
+}
+
+// Info returns the neighbors of the switch from the CDP cache and the LLDP remote systems.
+// The neighbor found by both protocols is reported once with the CDP information.
+func (c *Client) Info() (domain.DeviceReport, error) {
+	if c.snmp == nil {
+		return c.info, fmt.Errorf("snmp info [%v]: connection closed", c.info.Address)
+	}
+
+	ports, err := c.walkColumn(oidIfDescr)
+	if err != nil {
+		return c.info, fmt.Errorf("snmp info [%v]: %w", c.info.Address, err)
+	}
+
+	cdpNeighbors, err := c.cdpNeighbors(ports)
+	if err != nil {
+		return c.info, fmt.Errorf("snmp info [%v]: %w", c.info.Address, err)
+	}
+	lldpNeighbors, err := c.lldpNeighbors()
+	if err != nil {
+		return c.info, fmt.Errorf("snmp info [%v]: %w", c.info.Address, err)
+	}
+
+	c.info.Neighbors = cdpNeighbors
+	for _, neighbor := range lldpNeighbors {
+		if !knownNeighbor(cdpNeighbors, neighbor) {
+			c.info.Neighbors = append(c.info.Neighbors, neighbor)
+		}
+	}
+
+	return c.info, nil
+}
+
+// cdpNeighbors reads the CDP cache. ports maps the ifIndex to the name of the local port.
+func (c *Client) cdpNeighbors(ports map[string]gosnmp.SnmpPDU) ([]domain.NeighborReport, error) {
+	rows, err := c.walkTable(oidCDPCacheEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbors []domain.NeighborReport
+	for _, index := range sortedKeys(rows) {
+		row := rows[index]
+		if addressType, ok := row[cdpAddressType]; ok && intValue(addressType) != addressTypeIP && intValue(addressType) != cdpAddressTypeIPv6 {
+			continue
+		}
+
+		neighbor := domain.NeighborReport{
+			Name:       stringValue(row[cdpDeviceID]),
+			Platform:   strings.TrimSpace(strings.TrimPrefix(stringValue(row[cdpPlatform]), "cisco ")),
+			Version:    parseVersion(stringValue(row[cdpVersion])),
+			RemotePort: stringValue(row[cdpDevicePort]),
+			NativeVLAN: intValue(row[cdpNativeVLAN]),
+		}
+		if neighbor.SetAddresses(ipValue(row[cdpAddress])); neighbor.Address == "" {
+			continue
+		}
+		ifIndex := strings.SplitN(index, ".", 2)[0]
+		if port, ok := ports[ifIndex]; ok {
+			neighbor.LocalPort = stringValue(port)
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors, nil
+}
+
+// lldpNeighbors reads the LLDP remote systems. The neighbors without the ip address of management are skipped.
+func (c *Client) lldpNeighbors() ([]domain.NeighborReport, error) {
+	rows, err := c.walkTable(oidLLDPRemEntry)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	localPorts, err := c.walkColumn(oidLLDPLocPortID)
+	if err != nil {
+		return nil, err
+	}
+
+	//the management address is a part of the index of lldpRemManAddrTable
+	addresses := make(map[string][]string)
+	err = c.snmp.BulkWalk(oidLLDPRemManAddrEntry, func(variable gosnmp.SnmpPDU) error {
+		parts := strings.Split(strings.TrimPrefix(variable.Name, oidLLDPRemManAddrEntry+"."), ".")
+		//column.timeMark.localPortNum.index.addrSubtype.addrLen.a.b.c.d (16 octets of IPv6)
+		if len(parts) < 6 {
+			return nil
+		}
+		if address := indexAddress(parts[4], parts[6:]); address != "" {
+			key := strings.Join(parts[1:4], ".")
+			addresses[key] = append(addresses[key], address)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbors []domain.NeighborReport
+	for _, index := range sortedKeys(rows) {
+		row := rows[index]
+		neighbor := domain.NeighborReport{
+			Name:       stringValue(row[lldpRemSysName]),
+			Version:    parseVersion(stringValue(row[lldpRemSysDesc])),
+			RemotePort: stringValue(row[lldpRemPortID]),
+		}
+		if neighbor.SetAddresses(addresses[index]...); neighbor.Address == "" {
+			continue
+		}
+		if parts := strings.Split(index, "."); len(parts) == 3 {
+			if port, ok := localPorts[parts[1]]; ok {
+				neighbor.LocalPort = stringValue(port)
+			}
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+
+	return neighbors, nil
+}
+
+// walkTable returns the rows of the table by their index. Every row maps the number of the column to the value.
+func (c *Client) walkTable(entryOID string) (map[string]map[int]gosnmp.SnmpPDU, error) {
+	rows := make(map[string]map[int]gosnmp.SnmpPDU)
+	err := c.snmp.BulkWalk(entryOID, func(variable gosnmp.SnmpPDU) error {
+		parts := strings.SplitN(strings.TrimPrefix(variable.Name, entryOID+"."), ".", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		column, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil
+		}
+
+		if rows[parts[1]] == nil {
+			rows[parts[1]] = make(map[int]gosnmp.SnmpPDU)
+		}
+		rows[parts[1]][column] = variable
+		return nil
+	})
+
+	return rows, err
+}
+
+// walkColumn returns the values of the column of the table by their index
+func (c *Client) walkColumn(columnOID string) (map[string]gosnmp.SnmpPDU, error) {
+	values := make(map[string]gosnmp.SnmpPDU)
+	err := c.snmp.BulkWalk(columnOID, func(variable gosnmp.SnmpPDU) error {
+		values[strings.TrimPrefix(variable.Name, columnOID+".")] = variable
+		return nil
+	})
+
+	return values, err
+}
+
+// knownNeighbor reports whether the neighbor is already in the list with the same address and local port
+func knownNeighbor(neighbors []domain.NeighborReport, neighbor domain.NeighborReport) bool {
+	for _, known := range neighbors {
+		if known.Address == neighbor.Address && domain.EquivalentPort(known.LocalPort, neighbor.LocalPort) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stringValue(variable gosnmp.SnmpPDU) string {
+	if value, ok := variable.Value.([]byte); ok {
+		return strings.TrimSpace(string(value))
+	}
+	if value, ok := variable.Value.(string); ok {
+		return strings.TrimSpace(value)
+	}
+
+	return ""
+}
+
+func intValue(variable gosnmp.SnmpPDU) int {
+	if variable.Value == nil {
+		return 0
+	}
+
+	return int(gosnmp.ToBigInt(variable.Value).Int64())
+}
+
+// ipValue converts the address of the CDP cache (4 bytes of IPv4 or 16 bytes of IPv6) to the text form
+func ipValue(variable gosnmp.SnmpPDU) string {
+	value, ok := variable.Value.([]byte)
+	if !ok || (len(value) != net.IPv4len && len(value) != net.IPv6len) {
+		return ""
+	}
+
+	return net.IP(value).String()
+}
+
+// indexAddress converts the address of the index of lldpRemManAddrTable to the text form.
+// The address is empty if it is not IPv4 or IPv6.
+func indexAddress(subtype string, octets []string) string {
+	var size int
+	switch subtype {
+	case strconv.Itoa(addressTypeIP):
+		size = net.IPv4len
+	case strconv.Itoa(addressTypeIPv6):
+		size = net.IPv6len
+	default:
+		return ""
+	}
+	if len(octets) != size {
+		return ""
+	}
+
+	ip := make(net.IP, size)
+	for i, octet := range octets {
+		value, err := strconv.Atoi(octet)
+		if err != nil || value < 0 || value > 255 {
+			return ""
+		}
+		ip[i] = byte(value)
+	}
+
+	return ip.String()
+}
+
+// parseVersion extracts the version number from the description of the software, if possible
+func parseVersion(description string) string {
+	if version := versionRe.FindStringSubmatch(description); version != nil {
+		return version[1]
+	}
+
+	return strings.TrimSpace(strings.SplitN(description, "\n", 2)[0])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lessIndex(keys[i], keys[j])
+	})
+
+	return keys
+}
+
+// lessIndex compares the indexes of the rows numerically part by part
+func lessIndex(a, b string) bool {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numberA, _ := strconv.Atoi(partsA[i])
+		numberB, _ := strconv.Atoi(partsB[i])
+		if numberA != numberB {
+			return numberA < numberB
+		}
+	}
+
+	return len(partsA) < len(partsB)
+}
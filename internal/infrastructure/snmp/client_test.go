@@ -0,0 +1,234 @@
+package snmp
+
+import (
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// fakeAgent plays the SNMP v2c agent of the switch: Get and GetBulk are answered from the prepared variables
+type fakeAgent struct {
+	conn      net.PacketConn
+	variables map[string]gosnmp.SnmpPDU
+	oids      []string //sorted numerically
+}
+
+func newFakeAgent(t *testing.T, variables []gosnmp.SnmpPDU) *fakeAgent {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	agent := &fakeAgent{conn: conn, variables: make(map[string]gosnmp.SnmpPDU)}
+	for _, variable := range variables {
+		agent.variables[variable.Name] = variable
+		agent.oids = append(agent.oids, variable.Name)
+	}
+	sort.Slice(agent.oids, func(i, j int) bool {
+		return lessIndex(agent.oids[i][1:], agent.oids[j][1:])
+	})
+
+	go agent.serve()
+
+	return agent
+}
+
+func (a *fakeAgent) port() uint16 {
+	return uint16(a.conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+func (a *fakeAgent) serve() {
+	decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		request, err := decoder.SnmpDecodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		response := &gosnmp.SnmpPacket{
+			Version:   request.Version,
+			Community: request.Community,
+			PDUType:   gosnmp.GetResponse,
+			RequestID: request.RequestID,
+			Variables: a.answer(request),
+		}
+		if out, err := response.MarshalMsg(); err == nil {
+			a.conn.WriteTo(out, addr)
+		}
+	}
+}
+
+func (a *fakeAgent) answer(request *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	var variables []gosnmp.SnmpPDU
+	for _, requested := range request.Variables {
+		switch request.PDUType {
+		case gosnmp.GetRequest:
+			variable, ok := a.variables[requested.Name]
+			if !ok {
+				variable = gosnmp.SnmpPDU{Name: requested.Name, Type: gosnmp.NoSuchObject}
+			}
+			variables = append(variables, variable)
+		default:
+			repetitions := 1
+			if request.PDUType == gosnmp.GetBulkRequest {
+				repetitions = 10 //the decoder of gosnmp does not fill MaxRepetitions of the requests
+			}
+			variables = append(variables, a.next(requested.Name, repetitions)...)
+		}
+	}
+
+	return variables
+}
+
+// next returns the variables following the oid in the lexicographic order of the MIB
+func (a *fakeAgent) next(oid string, count int) []gosnmp.SnmpPDU {
+	i := sort.Search(len(a.oids), func(i int) bool {
+		return lessIndex(oid[1:], a.oids[i][1:]) && a.oids[i] != oid
+	})
+
+	var variables []gosnmp.SnmpPDU
+	for ; i < len(a.oids) && len(variables) < count; i++ {
+		variables = append(variables, a.variables[a.oids[i]])
+	}
+	if len(variables) < count {
+		variables = append(variables, gosnmp.SnmpPDU{Name: oid, Type: gosnmp.EndOfMibView})
+	}
+
+	return variables
+}
+
+func octets(name string, value string) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{Name: name, Type: gosnmp.OctetString, Value: []byte(value)}
+}
+
+func integer(name string, value int) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{Name: name, Type: gosnmp.Integer, Value: value}
+}
+
+func TestClient_Info(t *testing.T) {
+	cdp := func(column string) string { return oidCDPCacheEntry + "." + column + ".10101.3" }
+	lldp := func(column string, index string) string { return oidLLDPRemEntry + "." + column + ".0." + index }
+
+	agent := newFakeAgent(t, []gosnmp.SnmpPDU{
+		octets(oidSysDescr, "Cisco IOS Software, C3750E Software (C3750E-UNIVERSALK9-M), Version 15.0(2)SE11, RELEASE SOFTWARE (fc3)"),
+		octets(oidSysName, "core-1"),
+		octets(oidIfDescr+".10101", "GigabitEthernet0/1"),
+		octets(oidIfDescr+".10102", "GigabitEthernet0/2"),
+		octets(oidIfDescr+".10103", "GigabitEthernet0/3"),
+
+		integer(cdp("3"), addressTypeIP),
+		octets(cdp("4"), string([]byte{10, 0, 0, 2})),
+		octets(cdp("5"), "Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 12.2(55)SE7, RELEASE SOFTWARE (fc1)"),
+		octets(cdp("6"), "sw-2.corp.local"),
+		octets(cdp("7"), "GigabitEthernet0/24"),
+		octets(cdp("8"), "cisco WS-C2960-24TT-L"),
+		integer(cdp("11"), 10),
+
+		//the CDP neighbor is reported by LLDP too
+		octets(lldp("7", "1.1"), "Gi0/24"),
+		octets(lldp("9", "1.1"), "sw-2.corp.local"),
+		octets(lldp("7", "3.2"), "1/1/48"),
+		octets(lldp("9", "3.2"), "aruba-1"),
+		octets(lldp("10", "3.2"), "Aruba JL256A 2930F-48G-PoEP, revision WC.16.10.0009"),
+		//the neighbor without the management address
+		octets(lldp("9", "2.3"), "phone"),
+
+		integer(oidLLDPRemManAddrEntry+".3.0.1.1.1.4.10.0.0.2", 2),
+		integer(oidLLDPRemManAddrEntry+".3.0.3.2.1.4.10.0.0.3", 2),
+		integer(oidLLDPRemManAddrEntry+".3.0.3.2.2.16.32.1.13.184.0.0.0.0.0.0.0.0.0.0.0.3", 2), //2001:db8::3
+
+		octets(oidLLDPLocPortID+".1", "Gi0/1"),
+		octets(oidLLDPLocPortID+".2", "Gi0/2"),
+		octets(oidLLDPLocPortID+".3", "Gi0/3"),
+	})
+
+	client := NewClient(WithPort(agent.port()), WithTimeout(time.Second, 0))
+	if err := client.Connect("127.0.0.1", "", ""); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Close()
+
+	got, err := client.Info()
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+
+	want := domain.DeviceReport{
+		Name:    "core-1",
+		Address: "127.0.0.1",
+		Version: "15.0(2)SE11",
+		Neighbors: []domain.NeighborReport{
+			{
+				Name:       "sw-2.corp.local",
+				Address:    "10.0.0.2",
+				Addresses:  []string{"10.0.0.2"},
+				Platform:   "WS-C2960-24TT-L",
+				Version:    "12.2(55)SE7",
+				LocalPort:  "GigabitEthernet0/1",
+				RemotePort: "GigabitEthernet0/24",
+				NativeVLAN: 10,
+			},
+			{
+				Name:       "aruba-1",
+				Address:    "10.0.0.3",
+				Addresses:  []string{"10.0.0.3", "2001:db8::3"},
+				Version:    "Aruba JL256A 2930F-48G-PoEP, revision WC.16.10.0009",
+				LocalPort:  "Gi0/3",
+				RemotePort: "1/1/48",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Info() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_ConnectTimeout(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+
+	client := NewClient(WithPort(uint16(conn.LocalAddr().(*net.UDPAddr).Port)), WithTimeout(100*time.Millisecond, 0))
+	if err := client.Connect("127.0.0.1", "", ""); err == nil {
+		client.Close()
+		t.Errorf("Connect() to the silent agent error = nil, want timeout")
+	}
+}
+
+func TestCheckProtocols(t *testing.T) {
+	tests := []struct {
+		name         string
+		authProtocol string
+		privProtocol string
+		wantErr      bool
+	}{
+		{"none", "", "", false},
+		{"sha and aes", "SHA", "AES", false},
+		{"lower case", "md5", "des", false},
+		{"unknown auth", "SHA512X", "", true},
+		{"unknown priv", "SHA", "3DES-X", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckProtocols(tt.authProtocol, tt.privProtocol); (err != nil) != tt.wantErr {
+				t.Errorf("CheckProtocols() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import "net"
+
+// Backends - registry of the clients polling the switches. The client of the switch is selected by its address:
+// the first added backend whose filter allows the address is used, otherwise the default client.
+type Backends struct {
+	fallback Client
+	backends []backend
+}
+
+type backend struct {
+	filter IPFilter
+	client Client
+}
+
+func NewBackends(fallback Client) *Backends {
+	return &Backends{fallback: fallback}
+}
+
+// Add registers the client for the switches allowed by the filter, e.g. for a subnet or a list of devices
+func (b *Backends) Add(filter IPFilter, client Client) {
+	b.backends = append(b.backends, backend{filter: filter, client: client})
+}
+
+// Client returns the client that polls the switch with the address
+func (b *Backends) Client(address string) Client {
+	ip := net.ParseIP(address)
+	for _, backend := range b.backends {
+		if backend.filter.Allow(ip) {
+			return backend.client
+		}
+	}
+
+	return b.fallback
+}
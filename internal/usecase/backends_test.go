@@ -0,0 +1,63 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+	"github.com/vps2/cisco-switches-crawler/pkg/ip"
+)
+
+type namedClient string
+
+func (c namedClient) Connect(string, string, string) error {
+	return nil
+}
+
+func (c namedClient) Close() error {
+	return nil
+}
+
+func (c namedClient) Info() (domain.DeviceReport, error) {
+	return domain.DeviceReport{Name: string(c)}, nil
+}
+
+func newFilter(t *testing.T, addresses ...string) *ip.Filter {
+	t.Helper()
+
+	filter := ip.NewFilter()
+	for _, address := range addresses {
+		if err := filter.Add(address); err != nil {
+			t.Fatalf("Filter.Add(%s) error = %v", address, err)
+		}
+	}
+
+	return filter
+}
+
+func TestBackends_Client(t *testing.T) {
+	backends := usecase.NewBackends(namedClient("telnet"))
+	backends.Add(newFilter(t, "10.0.1.1"), namedClient("ssh"))
+	backends.Add(newFilter(t, "10.0.1.0/24", "10.0.2.0/24", "2001:db8:1::/48"), namedClient("snmp"))
+
+	tests := []struct {
+		address string
+		want    namedClient
+	}{
+		{"10.0.1.1", "ssh"},
+		{"10.0.1.2", "snmp"},
+		{"10.0.2.200", "snmp"},
+		{"10.0.3.1", "telnet"},
+		{"2001:db8:1:2::1", "snmp"},
+		{"2001:db8:2::1", "telnet"},
+		{"", "telnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := backends.Client(tt.address); got != tt.want {
+				t.Errorf("Backends.Client() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
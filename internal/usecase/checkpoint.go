@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/pkg/queue"
+)
+
+// Checkpoint - state of the unfinished crawl. The results of the polled switches (status, attempts, errors)
+// are kept by the switches of the network.
+type Checkpoint struct {
+	Network *domain.Network
+	Queue   []string //the addresses of the switches waiting for the poll
+	Visited []string //the addresses of the polled switches
+}
+
+// CheckpointStore - storage of the checkpoints of the crawl
+type CheckpointStore interface {
+	Save(checkpoint Checkpoint) error
+}
+
+// WithCheckpoint saves the state of the crawl to the store at most once per the interval
+// and when the crawl is finished or interrupted
+func WithCheckpoint(store CheckpointStore, interval time.Duration) Option {
+	return func(nb *NetworkBuilder) {
+		nb.checkpointStore = store
+		nb.checkpointInterval = interval
+	}
+}
+
+// WithResume continues the crawl from the checkpoint: the polled switches are skipped,
+// the failed ones are polled again before the switches of the queue
+func WithResume(checkpoint Checkpoint) Option {
+	return func(nb *NetworkBuilder) {
+		nb.network = checkpoint.Network
+		nb.resume = &checkpoint
+	}
+}
+
+// resumeCrawl pushes the switches to poll of the checkpoint to the queue and returns the switches polled before
+func (nb *NetworkBuilder) resumeCrawl(queue *queue.Queue[*domain.Switch]) []*domain.Switch {
+	if nb.resume == nil {
+		return nil
+	}
+
+	var visited []*domain.Switch
+	for _, address := range nb.resume.Visited {
+		sw, err := nb.network.Switch(address)
+		if err != nil {
+			continue
+		}
+		if sw.Status() == domain.StatusFailed {
+			queue.Push(&sw)
+			continue
+		}
+		visited = append(visited, &sw)
+	}
+	for _, address := range nb.resume.Queue {
+		if sw, err := nb.network.Switch(address); err == nil {
+			queue.Push(&sw)
+		}
+	}
+
+	return visited
+}
+
+// saveCheckpoint saves the state of the crawl if the interval has passed since the last save or if force is set
+func (nb *NetworkBuilder) saveCheckpoint(queue *queue.Queue[*domain.Switch], visited []*domain.Switch, force bool) {
+	if nb.checkpointStore == nil || (!force && time.Since(nb.checkpointSaved) < nb.checkpointInterval) {
+		return
+	}
+
+	checkpoint := Checkpoint{Network: nb.network, Queue: []string{}, Visited: []string{}}
+	for _, sw := range queue.Values() {
+		checkpoint.Queue = append(checkpoint.Queue, sw.Address())
+	}
+	for _, sw := range visited {
+		checkpoint.Visited = append(checkpoint.Visited, sw.Address())
+	}
+
+	if err := nb.checkpointStore.Save(checkpoint); err != nil {
+		log.Println(err)
+	}
+	nb.checkpointSaved = time.Now()
+}
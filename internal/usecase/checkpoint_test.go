@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+// checkpointStore keeps the saved checkpoints
+type checkpointStore []usecase.Checkpoint
+
+func (s *checkpointStore) Save(checkpoint usecase.Checkpoint) error {
+	*s = append(*s, checkpoint)
+	return nil
+}
+
+func TestNetworkBuilder_BuildResume(t *testing.T) {
+	network := domain.NewNetwork()
+	sw1, _ := domain.NewSwitch("10.0.0.1")
+	sw1.SetStatus(domain.StatusCrawled)
+	sw2, _ := domain.NewSwitch("10.0.0.2")
+	sw2.SetStatus(domain.StatusDiscarded)
+	network.AddSwitch(*sw1)
+	network.AddSwitch(*sw2)
+
+	var store checkpointStore
+	client := &recordingClient{}
+	builder := usecase.NewNetworkBuilder(client,
+		usecase.WithResume(usecase.Checkpoint{Network: network, Queue: []string{}, Visited: []string{"10.0.0.1"}}),
+		usecase.WithCheckpoint(&store, 0),
+		usecase.WithPollInterval(time.Millisecond))
+	builder.Build(context.Background(), "10.0.0.1", "admin", "admin-pass")
+
+	if len(client.connections) != 0 {
+		t.Errorf("connections = %v, want the crawled switches skipped", client.connections)
+	}
+	if builder.Network() != network || network.Len() != 2 {
+		t.Errorf("Network() = %v, want the network of the checkpoint", builder.Network())
+	}
+	if len(store) != 1 {
+		t.Fatalf("checkpoints = %d, want the final one", len(store))
+	}
+	if got := store[0]; len(got.Queue) != 0 || !reflect.DeepEqual(got.Visited, []string{"10.0.0.1"}) {
+		t.Errorf("checkpoint = queue %v, visited %v, want the visited switch", got.Queue, got.Visited)
+	}
+}
+
+func TestNetworkBuilder_BuildResumeQueue(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		wantSaves [][]string //the queue and the visited switches of every saved checkpoint
+	}{
+		{"every switch", 0, [][]string{
+			{"10.0.0.3 10.0.0.4", "10.0.0.1"},
+			{"10.0.0.4", "10.0.0.1 10.0.0.3"},
+			{"", "10.0.0.1 10.0.0.3 10.0.0.4"},
+		}},
+		{"long interval", time.Hour, [][]string{
+			{"10.0.0.3 10.0.0.4", "10.0.0.1"},
+			{"", "10.0.0.1 10.0.0.3 10.0.0.4"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := domain.NewNetwork()
+			for address, status := range map[string]domain.Status{
+				"10.0.0.1": domain.StatusCrawled,
+				"10.0.0.3": domain.StatusFailed,
+				"10.0.0.4": domain.StatusNotCrawled,
+			} {
+				sw, _ := domain.NewSwitch(address)
+				sw.SetStatus(status)
+				network.AddSwitch(*sw)
+			}
+
+			var store checkpointStore
+			client := &recordingClient{}
+			builder := usecase.NewNetworkBuilder(client,
+				usecase.WithResume(usecase.Checkpoint{Network: network, Queue: []string{"10.0.0.4"}, Visited: []string{"10.0.0.1", "10.0.0.3"}}),
+				usecase.WithCheckpoint(&store, tt.interval),
+				usecase.WithPollInterval(time.Millisecond))
+			builder.Build(context.Background(), "", "admin", "admin-pass")
+
+			//the failed switch is polled again before the queue
+			if want := []string{"10.0.0.3 admin:admin-pass", "10.0.0.4 admin:admin-pass"}; !reflect.DeepEqual(client.connections, want) {
+				t.Errorf("connections = %v, want %v", client.connections, want)
+			}
+			if sw, _ := network.Switch("10.0.0.3"); sw.Status() != domain.StatusCrawled {
+				t.Errorf("status = %v, want %v", sw.Status(), domain.StatusCrawled)
+			}
+
+			var saves [][]string
+			for _, checkpoint := range store {
+				saves = append(saves, []string{strings.Join(checkpoint.Queue, " "), strings.Join(checkpoint.Visited, " ")})
+			}
+			if !reflect.DeepEqual(saves, tt.wantSaves) {
+				t.Errorf("checkpoints = %q, want %q", saves, tt.wantSaves)
+			}
+		})
+	}
+}
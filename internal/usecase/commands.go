@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+var ErrCommandNotAllowed = errors.New("the command is not in the read-only allowlist")
+
+// readOnlyCommands - commands (or their abbreviations) that do not change the state of the switch
+var readOnlyCommands = []string{"show", "display", "dir"}
+
+// forbiddenPipes - output modifiers that write the output of the command somewhere on the switch or the network
+var forbiddenPipes = []string{"redirect", "tee", "append"}
+
+// CommandClient - client able to execute arbitrary commands on the switch
+type CommandClient interface {
+	Run(command string) (string, error)
+}
+
+// CommandStore - storage of the outputs of the commands executed on the switches
+type CommandStore interface {
+	Save(result DeviceCommands) error
+}
+
+// CommandResult - output of the command executed on the switch
+type CommandResult struct {
+	Command string
+	Output  string
+	Error   string
+}
+
+// DeviceCommands - results of the commands executed on the switch
+type DeviceCommands struct {
+	Name     string
+	Address  string
+	Error    string //the error of the connection to the switch
+	Attempts int    //the number of the connections to the switch, including the retries
+	Results  []CommandResult
+}
+
+// CheckReadOnly returns an error if the command is not known as read-only.
+// Abbreviations of the commands are allowed, e.g. "sh ver".
+func CheckReadOnly(command string) error {
+	//the line break would send the next command unchecked, ">" redirects the output to a file on NX-OS
+	if strings.ContainsAny(command, "\r\n>") {
+		return fmt.Errorf("check command [%q]: %w", command, ErrCommandNotAllowed)
+	}
+
+	parts := strings.Split(command, "|")
+
+	words := strings.Fields(parts[0])
+	if len(words) == 0 {
+		return fmt.Errorf("check command [%s]: %w", command, ErrCommandNotAllowed)
+	}
+
+	allowed := false
+	for _, readOnly := range readOnlyCommands {
+		if len(words[0]) >= 2 && strings.HasPrefix(readOnly, strings.ToLower(words[0])) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("check command [%s]: %w", command, ErrCommandNotAllowed)
+	}
+
+	for _, pipe := range parts[1:] {
+		modifier := strings.Fields(pipe)
+		if len(modifier) == 0 {
+			continue
+		}
+		for _, forbidden := range forbiddenPipes {
+			if strings.HasPrefix(forbidden, strings.ToLower(modifier[0])) {
+				return fmt.Errorf("check command [%s]: %w", command, ErrCommandNotAllowed)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runCommands executes the commands on the switch the client is connected to
+func runCommands(client Client, commands []string) []CommandResult {
+	results := make([]CommandResult, 0, len(commands))
+
+	commandClient, ok := client.(CommandClient)
+	for _, command := range commands {
+		result := CommandResult{Command: command}
+		if !ok {
+			result.Error = "the client can not execute commands"
+		} else if output, err := commandClient.Run(command); err != nil {
+			result.Output = output
+			result.Error = err.Error()
+		} else {
+			result.Output = output
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Executor executes commands on the switches of the network built earlier
+type Executor struct {
+	client     Client
+	commands   []string
+	store      CommandStore
+	profiles   ProfileResolver
+	retry      RetryPolicy
+	showOutput bool
+}
+
+type ExecutorOption func(*Executor)
+
+func WithExecutorShowOutput() ExecutorOption {
+	return func(e *Executor) {
+		e.showOutput = true
+	}
+}
+
+// WithExecutorProfiles connects to the switches with the clients and the credentials of the matching profiles
+func WithExecutorProfiles(profiles ProfileResolver) ExecutorOption {
+	return func(e *Executor) {
+		e.profiles = profiles
+	}
+}
+
+// WithExecutorRetry repeats the connections to the switches failed with the transient errors, see IsRetryable
+func WithExecutorRetry(policy RetryPolicy) ExecutorOption {
+	return func(e *Executor) {
+		e.retry = policy
+	}
+}
+
+func NewExecutor(cl Client, commands []string, store CommandStore, opts ...ExecutorOption) *Executor {
+	e := &Executor{
+		client:   cl,
+		commands: commands,
+		store:    store,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Run executes the commands on every switch of the network, except for the switches discarded by the filter
+func (e *Executor) Run(ctx context.Context, network *domain.Network, user string, password string) {
+	timerDuration := defaultPollInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for _, sw := range network.Switches() {
+		if sw.Status() == domain.StatusDiscarded {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		result := DeviceCommands{Name: sw.Name(), Address: sw.Address()}
+		conn := resolveConnection(e.profiles, sw, e.client, user, password)
+		if e.showOutput && conn.Profile != "" {
+			log.Printf("connect [%s]: %s", sw.Address(), conn.Profile)
+		}
+		if hintClient, ok := conn.Client.(HintClient); ok {
+			hintClient.Hint(sw.Name(), sw.Platform())
+		}
+		attempts, err := connect(ctx, e.retry, conn.Client, sw.Address(), conn.User, conn.Password)
+		result.Attempts = attempts
+		if err != nil {
+			if e.showOutput {
+				log.Println()
+			}
+			log.Println(err)
+			result.Error = err.Error()
+		} else {
+			result.Results = runCommands(conn.Client, e.commands)
+			conn.Client.Close()
+		}
+
+		if err := e.store.Save(result); err != nil {
+			log.Println(err)
+		}
+
+		timer.Reset(timerDuration)
+	}
+}
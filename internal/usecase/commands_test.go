@@ -0,0 +1,47 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{"show version", true},
+		{"sh ver", true},
+		{"SHOW VLAN BRIEF", true},
+		{"show running-config | include hostname", true},
+		{"display interface brief", true},
+		{"dir flash:", true},
+		{"s ver", false},
+		{"configure terminal", false},
+		{"reload", false},
+		{"copy running-config tftp:", false},
+		{"show running-config | redirect tftp://10.0.0.1/sw.cfg", false},
+		{"show tech | tee flash:tech.txt", false},
+		{"show log | append flash:log.txt", false},
+		{"show version\nreload", false},
+		{"show clock\r\nconfigure terminal", false},
+		{"show running-config > bootflash:run.cfg", false},
+		{"show tech >> bootflash:tech.txt", false},
+		{"show running-config >bootflash:run.cfg", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			err := usecase.CheckReadOnly(tt.command)
+			if tt.allowed && err != nil {
+				t.Errorf("CheckReadOnly() error = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, usecase.ErrCommandNotAllowed) {
+				t.Errorf("CheckReadOnly() error = %v, want %v", err, usecase.ErrCommandNotAllowed)
+			}
+		})
+	}
+}
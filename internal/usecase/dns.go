@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+const (
+	lookupTimeout = 5 * time.Second
+	lookupWorkers = 16 //the parallel lookups of the names
+)
+
+// Resolver - resolver of the host names and the addresses, e.g. net.DefaultResolver
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// DNS resolves the host names of the seeds and looks up the names of the crawled switches
+type DNS struct {
+	resolver Resolver
+}
+
+func NewDNS(resolver Resolver) *DNS {
+	return &DNS{resolver: resolver}
+}
+
+// ResolveSeed returns the address of the seed given by the ip address or the host name.
+// If the name has the addresses of both families, the address of the family is preferred.
+func (d *DNS) ResolveSeed(ctx context.Context, seed string, family domain.AddressFamily) (string, error) {
+	if ip := net.ParseIP(seed); ip != nil {
+		return ip.String(), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	hosts, err := d.resolver.LookupHost(ctx, seed)
+	if err != nil {
+		return "", fmt.Errorf("dns resolve [%s]: %w", seed, err)
+	}
+
+	var addresses []string
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			addresses = append(addresses, ip.String())
+		}
+	}
+	address := domain.SelectAddress(addresses, family)
+	if address == "" {
+		return "", fmt.Errorf("dns resolve [%s]: %w", seed, domain.ErrUnresolvedHost)
+	}
+
+	return address, nil
+}
+
+// LookupName returns the DNS name of the address by its PTR record or the empty name if there is no record
+func (d *DNS) LookupName(ctx context.Context, address string) string {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	names, err := d.resolver.LookupAddr(ctx, address)
+	if err != nil || len(names) == 0 {
+		return ""
+	}
+
+	return names[0]
+}
+
+// LookupNames sets the DNS names of the switches of the network without the names by the PTR records of their addresses.
+// The lookups run in parallel by lookupWorkers at most. The switches without the records keep the empty name.
+func (d *DNS) LookupNames(ctx context.Context, network *domain.Network) {
+	type lookup struct {
+		address string
+		name    string
+	}
+
+	var pending []string
+	for _, sw := range network.Switches() {
+		if sw.DNSName() == "" {
+			pending = append(pending, sw.Address())
+		}
+	}
+
+	addresses := make(chan string)
+	results := make(chan lookup)
+
+	var wg sync.WaitGroup
+	for i := 0; i < lookupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range addresses {
+				results <- lookup{address: address, name: d.LookupName(ctx, address)}
+			}
+		}()
+	}
+	go func() {
+		defer close(addresses)
+		for _, address := range pending {
+			select {
+			case addresses <- address:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results { //the network is updated by one goroutine
+		if result.name == "" {
+			continue
+		}
+		if sw, err := network.Switch(result.address); err == nil {
+			sw.SetDNSName(result.name)
+			network.UpdateSwitch(sw)
+		}
+	}
+}
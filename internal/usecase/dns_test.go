@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+var errNoSuchHost = errors.New("no such host")
+
+// fakeResolver answers the lookups from the maps of the names and the addresses
+type fakeResolver struct {
+	hosts map[string][]string
+	names map[string][]string
+}
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addresses, ok := r.hosts[host]; ok {
+		return addresses, nil
+	}
+	return nil, errNoSuchHost
+}
+
+func (r fakeResolver) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := r.names[addr]; ok {
+		return names, nil
+	}
+	return nil, errNoSuchHost
+}
+
+func TestDNS_ResolveSeed(t *testing.T) {
+	dns := usecase.NewDNS(fakeResolver{hosts: map[string][]string{
+		"core1.corp.local": {"2001:db8::1", "10.0.0.1"},
+		"core2.corp.local": {"2001:db8::2"},
+		"empty.corp.local": {},
+	}})
+
+	tests := []struct {
+		name    string
+		seed    string
+		family  domain.AddressFamily
+		want    string
+		wantErr error
+	}{
+		{"ip address", "10.0.0.5", domain.FamilyIPv4, "10.0.0.5", nil},
+		{"ipv6 address", "2001:DB8::5", domain.FamilyIPv4, "2001:db8::5", nil},
+		{"prefer ipv4", "core1.corp.local", domain.FamilyIPv4, "10.0.0.1", nil},
+		{"prefer ipv6", "core1.corp.local", domain.FamilyIPv6, "2001:db8::1", nil},
+		{"only ipv6", "core2.corp.local", domain.FamilyIPv4, "2001:db8::2", nil},
+		{"no addresses", "empty.corp.local", domain.FamilyIPv4, "", domain.ErrUnresolvedHost},
+		{"unknown host", "core3.corp.local", domain.FamilyIPv4, "", errNoSuchHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dns.ResolveSeed(context.Background(), tt.seed, tt.family)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DNS.ResolveSeed() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DNS.ResolveSeed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDNS_LookupNames(t *testing.T) {
+	network := domain.NewNetwork()
+	for address, name := range map[string]string{"10.0.0.1": "CORE1", "10.0.0.2": "ACC2", "10.0.0.3": "ACC3"} {
+		sw, _ := domain.NewSwitch(address)
+		sw.SetName(name)
+		network.AddSwitch(*sw)
+	}
+	seed, _ := domain.NewSwitch("10.0.0.4")
+	seed.SetDNSName("acc4.corp.local") //the name of the seed is already known
+	network.AddSwitch(*seed)
+
+	dns := usecase.NewDNS(fakeResolver{names: map[string][]string{
+		"10.0.0.1": {"core1.corp.local."},
+		"10.0.0.2": {"acc2-old.corp.local.", "acc2.corp.local."},
+		"10.0.0.4": {"acc4-old.corp.local."},
+	}})
+	dns.LookupNames(context.Background(), network)
+
+	tests := []struct {
+		address      string
+		wantName     string
+		wantMismatch bool
+	}{
+		{"10.0.0.1", "core1.corp.local", false},
+		{"10.0.0.2", "acc2-old.corp.local", true},
+		{"10.0.0.3", "", false},
+		{"10.0.0.4", "acc4.corp.local", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			sw, err := network.Switch(tt.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sw.DNSName() != tt.wantName || sw.DNSMismatch() != tt.wantMismatch {
+				t.Errorf("DNSName() = %v, DNSMismatch() = %v, want %v, %v", sw.DNSName(), sw.DNSMismatch(), tt.wantName, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestNetworkBuilder_BuildReverseDNS(t *testing.T) {
+	terminalServer := &recordingClient{name: "ts"}
+	profiles := fakeProfiles{
+		"ts-sw1.corp.local": {Client: terminalServer, User: "console", Password: "console-pass", Profile: "profile ts"},
+	}
+
+	client := &recordingClient{name: "default"}
+	builder := usecase.NewNetworkBuilder(client,
+		usecase.WithResolver(fakeResolver{names: map[string][]string{"10.0.0.1": {"ts-sw1.corp.local."}}}),
+		usecase.WithReverseDNS(),
+		usecase.WithProfiles(profiles),
+		usecase.WithPollInterval(time.Millisecond))
+	builder.Build(context.Background(), "10.0.0.1", "admin", "admin-pass")
+
+	if len(client.connections) != 0 || !reflect.DeepEqual(terminalServer.connections, []string{"10.0.0.1 console:console-pass"}) {
+		t.Errorf("connections = %v and %v, want the profile matched by the PTR name", client.connections, terminalServer.connections)
+	}
+	if sw, _ := builder.Network().Switch("10.0.0.1"); sw.DNSName() != "ts-sw1.corp.local" {
+		t.Errorf("DNSName() = %v, want ts-sw1.corp.local", sw.DNSName())
+	}
+}
@@ -9,14 +9,57 @@ import (
 	"time"
 
 	"github.com/vps2/cisco-switches-crawler/internal/domain"
-	"github.com/vps2/cisco-switches-crawler/internal/infrastructure/cisco"
 	"github.com/vps2/cisco-switches-crawler/pkg/queue"
 )
 
+// defaultPollInterval - the interval between the connections to the switches. If you do it more often,
+// then the management interface of the switches "falls off".
+const defaultPollInterval = 3 * time.Second
+
+// Client - backend polling the switch: telnet CLI, SNMP, etc.
 type Client interface {
 	Connect(address string, user string, password string) error
 	Close() error
-	Info() (cisco.ClientInfo, error) //TODO remove direct dependence on the infrastructure layer -> cisco.ClientInfo
+	Info() (domain.DeviceReport, error)
+}
+
+// ConfigClient - client able to get the configuration of the switch
+type ConfigClient interface {
+	RunningConfig() (string, error)
+}
+
+// ConfigStore - storage of the configurations of the switches
+type ConfigStore interface {
+	Save(hostname string, config string) error
+}
+
+// HostClient - client able to get the tables of the end hosts known to the switch
+type HostClient interface {
+	MACTable() ([]domain.MACEntry, error)
+	ARPTable() ([]domain.ARPEntry, error)
+}
+
+// VLANClient - client able to get the VLANs of the switch
+type VLANClient interface {
+	Trunks() ([]domain.TrunkPort, error)
+	VLANs() ([]domain.VLAN, error)
+}
+
+// STPClient - client able to get the state of the spanning tree of the switch
+type STPClient interface {
+	SpanningTree() ([]domain.STPInstance, error)
+}
+
+// PortChannelClient - client able to get the port-channels of the switch. The MAC address table, the trunks and
+// the spanning tree report the port-channels, while the neighbors are seen on their member ports.
+type PortChannelClient interface {
+	PortChannels() ([]domain.PortChannel, error)
+}
+
+// HintClient - client using the name and the platform of the switch known from its neighbors before the connection,
+// e.g. to select the dialect of the command line
+type HintClient interface {
+	Hint(name string, platform string)
 }
 
 type IPFilter interface {
@@ -37,17 +80,131 @@ func WithIPFiltering(filter IPFilter) Option {
 	}
 }
 
+// WithBackend polls the switches allowed by the filter with the client instead of the client of the builder.
+// The backends are checked in the order they are added.
+func WithBackend(filter IPFilter, client Client) Option {
+	return func(nb *NetworkBuilder) {
+		nb.backends.Add(filter, client)
+	}
+}
+
+// WithAddressFamily selects the address of the neighbors reporting the addresses of both families.
+// By default IPv4 addresses are preferred.
+func WithAddressFamily(family domain.AddressFamily) Option {
+	return func(nb *NetworkBuilder) {
+		nb.family = family
+	}
+}
+
+// WithResolver resolves the host names of the seeds and the names of the switches with the resolver
+// instead of the system one
+func WithResolver(resolver Resolver) Option {
+	return func(nb *NetworkBuilder) {
+		nb.dns = NewDNS(resolver)
+	}
+}
+
+// WithReverseDNS looks up the DNS names of the switches by their addresses: of the polled switches before the connection,
+// so the profiles can match them, and of the rest after the crawl
+func WithReverseDNS() Option {
+	return func(nb *NetworkBuilder) {
+		nb.reverseDNS = true
+	}
+}
+
+// WithProfiles connects to the switches with the clients and the credentials of the matching profiles
+func WithProfiles(profiles ProfileResolver) Option {
+	return func(nb *NetworkBuilder) {
+		nb.profiles = profiles
+	}
+}
+
+// WithPollInterval sets the interval between the connections to the switches, defaultPollInterval by default
+func WithPollInterval(interval time.Duration) Option {
+	return func(nb *NetworkBuilder) {
+		nb.pollInterval = interval
+	}
+}
+
+// WithRetry repeats the connections to the switches failed with the transient errors, see IsRetryable
+func WithRetry(policy RetryPolicy) Option {
+	return func(nb *NetworkBuilder) {
+		nb.retry = policy
+	}
+}
+
+// WithConfigBackup saves the configuration of every crawled switch to the store.
+// The client must implement ConfigClient.
+func WithConfigBackup(store ConfigStore) Option {
+	return func(nb *NetworkBuilder) {
+		nb.configStore = store
+	}
+}
+
+// WithCommands executes the commands on every crawled switch and saves their outputs to the store.
+// The client must implement CommandClient.
+func WithCommands(commands []string, store CommandStore) Option {
+	return func(nb *NetworkBuilder) {
+		nb.commands = commands
+		nb.commandStore = store
+	}
+}
+
+// WithHostTables collects the MAC address table and the ARP table of every crawled switch, so that the end hosts
+// can be located later. The client must implement HostClient.
+func WithHostTables() Option {
+	return func(nb *NetworkBuilder) {
+		nb.hostTables = true
+	}
+}
+
+// WithVLANs collects the trunks and the VLAN database of every crawled switch.
+// The client must implement VLANClient.
+func WithVLANs() Option {
+	return func(nb *NetworkBuilder) {
+		nb.vlans = true
+	}
+}
+
+// WithSpanningTree collects the state of the spanning tree of every crawled switch.
+// The client must implement STPClient.
+func WithSpanningTree() Option {
+	return func(nb *NetworkBuilder) {
+		nb.spanningTree = true
+	}
+}
+
 type NetworkBuilder struct {
-	network     *domain.Network
-	ciscoClient Client
-	ipFilter    IPFilter
-	showOutput  bool
+	network      *domain.Network
+	backends     *Backends
+	ipFilter     IPFilter
+	family       domain.AddressFamily
+	dns          *DNS
+	reverseDNS   bool
+	profiles     ProfileResolver
+	retry        RetryPolicy
+	pollInterval time.Duration
+	configStore  ConfigStore
+	commands     []string
+	commandStore CommandStore
+	hostTables   bool
+	vlans        bool
+	spanningTree bool
+	showOutput   bool
+
+	checkpointStore    CheckpointStore
+	checkpointInterval time.Duration
+	checkpointSaved    time.Time
+	resume             *Checkpoint
 }
 
 func NewNetworkBuilder(cl Client, opts ...Option) *NetworkBuilder {
 	nb := &NetworkBuilder{
-		network:     domain.NewNetwork(),
-		ciscoClient: cl,
+		network:      domain.NewNetwork(),
+		backends:     NewBackends(cl),
+		family:       domain.FamilyIPv4,
+		dns:          NewDNS(net.DefaultResolver),
+		pollInterval: defaultPollInterval,
 	}
 
 	for _, opt := range opts {
@@ -57,71 +214,336 @@ func NewNetworkBuilder(cl Client, opts ...Option) *NetworkBuilder {
 	return nb
 }
 
-func (nb *NetworkBuilder) Build(ctx context.Context, rootSwitchIP string, user string, password string) {
-	rootSwitch, _ := domain.NewSwitch(rootSwitchIP)
+// Build crawls the network starting from the seed switch given by the ip address or the host name.
+// The seed may be empty if the crawl is resumed from the checkpoint.
+func (nb *NetworkBuilder) Build(ctx context.Context, seed string, user string, password string) {
+	if nb.reverseDNS {
+		defer nb.dns.LookupNames(ctx, nb.network)
+	}
 
 	queue := queue.New[*domain.Switch]()
-	queue.Push(rootSwitch)
-
-	var visited []*domain.Switch
+	visited := nb.resumeCrawl(queue)
+	if seed != "" {
+		nb.addSeed(ctx, seed, queue)
+	}
 
-	timerDuration := 3 * time.Second //Switch polling interval. If you do it more often, then the management interface of the switches "falls off".
-	timer := time.NewTimer(timerDuration)
+	timer := time.NewTimer(nb.pollInterval)
 	defer timer.Stop()
 
 loop:
 	for !queue.IsEmpty() {
-		timer.Reset(timerDuration)
+		timer.Reset(nb.pollInterval)
 
 		select {
 		case <-ctx.Done():
 			break loop
 		case <-timer.C:
+			nb.saveCheckpoint(queue, visited, false)
 			currSwitch := queue.Pop()
 			if inList(visited, currSwitch) {
 				continue
 			}
 			visited = append(visited, currSwitch)
 
-			if err := nb.ciscoClient.Connect(currSwitch.Address(), user, password); err != nil {
+			known, _ := nb.network.Switch(currSwitch.Address())
+			if nb.reverseDNS && known.DNSName() == "" { //the profiles of the switch can be matched by the name
+				if name := nb.dns.LookupName(ctx, known.Address()); name != "" {
+					known.SetDNSName(name)
+					nb.network.UpdateSwitch(known)
+				}
+			}
+			conn := resolveConnection(nb.profiles, known, nb.backends.Client(currSwitch.Address()), user, password)
+			if nb.showOutput && conn.Profile != "" {
+				log.Printf("connect [%s]: %s", currSwitch.Address(), conn.Profile)
+			}
+			client := conn.Client
+			if hintClient, ok := client.(HintClient); ok {
+				hintClient.Hint(known.Name(), known.Platform())
+			}
+			attempts, err := connect(ctx, nb.retry, client, currSwitch.Address(), conn.User, conn.Password)
+			if err != nil {
 				if nb.showOutput {
 					log.Println()
 				}
 				log.Println(err)
+				nb.setResult(currSwitch, domain.StatusFailed, attempts, err)
 				continue
 			}
-			currSwitchInfo, err := nb.ciscoClient.Info()
+			var trunks []domain.TrunkPort
+			currSwitchInfo, err := client.Info()
 			if err != nil {
 				if nb.showOutput {
 					log.Println()
 				}
 				log.Println(err)
+				nb.setResult(currSwitch, domain.StatusFailed, attempts, err)
+			} else {
+				nb.setResult(currSwitch, domain.StatusCrawled, attempts, nil)
+				nb.backupConfig(client, currSwitchInfo.Name, currSwitch.Address())
+				nb.runCommands(client, currSwitchInfo.Name, currSwitch.Address())
+				nb.collectPortChannels(client, currSwitch.Address())
+				nb.collectHostTables(client, currSwitch.Address())
+				trunks = nb.collectVLANs(client, currSwitch.Address())
+				nb.collectSpanningTree(client, currSwitch.Address())
 			}
-			nb.ciscoClient.Close()
+			client.Close()
 
-			if rootSwitch == currSwitch {
-				currSwitch.SetName(currSwitchInfo.Name)
-				nb.network.AddSwitch(*currSwitch)
+			if sw, err := nb.network.Switch(currSwitch.Address()); err == nil {
+				if sw.Name() == "" {
+					sw.SetName(currSwitchInfo.Name)
+				}
+				if sw.Platform() == "" {
+					sw.SetPlatform(currSwitchInfo.Platform)
+				}
+				if sw.Version() == "" {
+					sw.SetVersion(currSwitchInfo.Version)
+				}
+				if currSwitchInfo.Serial != "" {
+					sw.SetSerial(currSwitchInfo.Serial)
+				}
+				nb.network.UpdateSwitch(sw)
 			}
 
+			currNetworkSwitch, _ := nb.network.Switch(currSwitch.Address())
 			for _, neighborInfo := range currSwitchInfo.Neighbors {
-				neighboringSwitch, _ := domain.NewSwitch(neighborInfo.Address)
+				neighborAddress := nb.neighborAddress(neighborInfo)
+				neighboringSwitch, err := domain.NewSwitch(neighborAddress)
+				if err != nil {
+					log.Println(err)
+					continue
+				}
 				neighboringSwitch.SetName(neighborInfo.Name)
+				neighboringSwitch.SetPlatform(neighborInfo.Platform)
+				neighboringSwitch.SetVersion(neighborInfo.Version)
+				neighboringSwitch.SetDepth(currNetworkSwitch.Depth() + 1)
 
 				if nb.ipFilter != nil {
-					if nb.ipFilter.Allow(net.ParseIP(neighborInfo.Address)) {
+					if nb.ipFilter.Allow(net.ParseIP(neighborAddress)) {
 						queue.Push(neighboringSwitch)
-
 					} else {
-						neighboringSwitch.SetName(neighborInfo.Name + ">>>DISCARDED")
+						neighboringSwitch.SetStatus(domain.StatusDiscarded)
 					}
 				}
 
-				nb.network.AddSwitch(*neighboringSwitch)
-				nb.network.AddLink(*currSwitch, *neighboringSwitch)
+				nb.addSwitch(*neighboringSwitch)
+				nb.network.AddLink(currNetworkSwitch, *neighboringSwitch,
+					domain.WithPorts(neighborInfo.LocalPort, neighborInfo.RemotePort),
+					domain.WithVLANs(trunkVLANs(currNetworkSwitch, trunks, neighborInfo.LocalPort), domain.PortVLANs{Native: neighborInfo.NativeVLAN}))
 			}
 		}
 	}
+	nb.saveCheckpoint(queue, visited, true)
+}
+
+// addSeed adds the seed switch to the network and to the queue unless it is already known, e.g. from the checkpoint
+func (nb *NetworkBuilder) addSeed(ctx context.Context, host string, queue *queue.Queue[*domain.Switch]) {
+	address, err := nb.dns.ResolveSeed(ctx, host, nb.family)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	seed, err := domain.NewSwitch(address)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if _, err := nb.network.Switch(seed.Address()); err == nil {
+		return
+	}
+	if net.ParseIP(host) == nil {
+		seed.SetDNSName(host) //the profiles of the switch can be matched by the name
+	}
+	nb.network.AddSwitch(*seed)
+	queue.Push(seed)
+}
+
+// Network returns the network built by the last crawl
+func (nb *NetworkBuilder) Network() *domain.Network {
+	return nb.network
+}
+
+// backupConfig saves the configuration of the switch the client is connected to
+func (nb *NetworkBuilder) backupConfig(client Client, name string, address string) {
+	if nb.configStore == nil {
+		return
+	}
+
+	configClient, ok := client.(ConfigClient)
+	if !ok {
+		log.Printf("backup config [%s]: the client can not get the configuration", address)
+		return
+	}
+
+	config, err := configClient.RunningConfig()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if name == "" {
+		name = address
+	}
+	if err := nb.configStore.Save(name, config); err != nil {
+		log.Println(err)
+	}
+}
+
+// runCommands executes the commands on the switch the client is connected to
+func (nb *NetworkBuilder) runCommands(client Client, name string, address string) {
+	if nb.commandStore == nil || len(nb.commands) == 0 {
+		return
+	}
+
+	result := DeviceCommands{Name: name, Address: address, Results: runCommands(client, nb.commands)}
+	if err := nb.commandStore.Save(result); err != nil {
+		log.Println(err)
+	}
+}
+
+// collectPortChannels saves the port-channels of the switch the client is connected to, if the host tables,
+// the VLANs or the spanning tree are collected. The clients unable to get them are skipped.
+func (nb *NetworkBuilder) collectPortChannels(client Client, address string) {
+	if !nb.hostTables && !nb.vlans && !nb.spanningTree {
+		return
+	}
+
+	channelClient, ok := client.(PortChannelClient)
+	if !ok {
+		return
+	}
+
+	channels, err := channelClient.PortChannels()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if sw, err := nb.network.Switch(address); err == nil {
+		sw.SetPortChannels(channels)
+		nb.network.UpdateSwitch(sw)
+	}
+}
+
+// collectHostTables saves the MAC address table and the ARP table of the switch the client is connected to
+func (nb *NetworkBuilder) collectHostTables(client Client, address string) {
+	if !nb.hostTables {
+		return
+	}
+
+	hostClient, ok := client.(HostClient)
+	if !ok {
+		log.Printf("host tables [%s]: the client can not get the tables of the hosts", address)
+		return
+	}
+
+	sw, err := nb.network.Switch(address)
+	if err != nil {
+		return
+	}
+
+	if entries, err := hostClient.MACTable(); err != nil {
+		log.Println(err)
+	} else {
+		sw.SetMACTable(entries)
+	}
+	if entries, err := hostClient.ARPTable(); err != nil {
+		log.Println(err)
+	} else {
+		sw.SetARPTable(entries)
+	}
+	nb.network.UpdateSwitch(sw)
+}
+
+// collectVLANs saves the VLAN database of the switch the client is connected to and returns its trunks
+func (nb *NetworkBuilder) collectVLANs(client Client, address string) []domain.TrunkPort {
+	if !nb.vlans {
+		return nil
+	}
+
+	vlanClient, ok := client.(VLANClient)
+	if !ok {
+		log.Printf("vlans [%s]: the client can not get the vlans", address)
+		return nil
+	}
+
+	if vlans, err := vlanClient.VLANs(); err != nil {
+		log.Println(err)
+	} else if sw, err := nb.network.Switch(address); err == nil {
+		sw.SetVLANs(vlans)
+		nb.network.UpdateSwitch(sw)
+	}
+
+	trunks, err := vlanClient.Trunks()
+	if err != nil {
+		log.Println(err)
+	}
+
+	return trunks
+}
+
+// collectSpanningTree saves the state of the spanning tree of the switch the client is connected to
+func (nb *NetworkBuilder) collectSpanningTree(client Client, address string) {
+	if !nb.spanningTree {
+		return
+	}
+
+	stpClient, ok := client.(STPClient)
+	if !ok {
+		log.Printf("spanning tree [%s]: the client can not get the spanning tree", address)
+		return
+	}
+
+	instances, err := stpClient.SpanningTree()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if sw, err := nb.network.Switch(address); err == nil {
+		sw.SetSTP(instances)
+		nb.network.UpdateSwitch(sw)
+	}
+}
+
+// neighborAddress returns the address of the neighbor already known to the network, so that the switch reachable
+// over both families is added once. Otherwise the address of the preferred family is used.
+func (nb *NetworkBuilder) neighborAddress(neighbor domain.NeighborReport) string {
+	for _, address := range neighbor.Addresses {
+		if _, err := nb.network.Switch(address); err == nil {
+			return address
+		}
+	}
+
+	return neighbor.PreferredAddress(nb.family)
+}
+
+// addSwitch adds the switch to the network. If the switch is already known,
+// the attributes missing in the network are taken from the new information.
+func (nb *NetworkBuilder) addSwitch(sw domain.Switch) {
+	known, err := nb.network.Switch(sw.Address())
+	if err != nil {
+		nb.network.AddSwitch(sw)
+		return
+	}
+
+	if known.Platform() == "" {
+		known.SetPlatform(sw.Platform())
+	}
+	if known.Version() == "" {
+		known.SetVersion(sw.Version())
+	}
+	nb.network.UpdateSwitch(known)
+}
+
+// setResult sets the result of the crawl of the switch: the status, the number of the connections and the error
+func (nb *NetworkBuilder) setResult(sw *domain.Switch, status domain.Status, attempts int, failure error) {
+	if networkSwitch, err := nb.network.Switch(sw.Address()); err == nil {
+		networkSwitch.SetStatus(status)
+		networkSwitch.SetAttempts(attempts)
+		networkSwitch.SetFailure("")
+		if failure != nil {
+			networkSwitch.SetFailure(failure.Error())
+		}
+		nb.network.UpdateSwitch(networkSwitch)
+	}
 }
 
 func (nb *NetworkBuilder) ToJSON() []byte {
@@ -135,6 +557,18 @@ func (nb *NetworkBuilder) ToPrettyJSON() []byte {
 	return prettyJSON.Bytes()
 }
 
+// trunkVLANs returns the VLANs of the trunk port of the switch. The member port of a port-channel is resolved
+// to the port-channel listed among the trunks. The VLANs are unknown if the port is not a trunk.
+func trunkVLANs(sw domain.Switch, trunks []domain.TrunkPort, port string) domain.PortVLANs {
+	for _, trunk := range trunks {
+		if sw.SamePort(trunk.Port, port) {
+			return trunk.VLANs
+		}
+	}
+
+	return domain.PortVLANs{}
+}
+
 func inList(container []*domain.Switch, sw *domain.Switch) bool {
 	for _, currSwitch := range container {
 		if currSwitch.Address() == sw.Address() {
@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+// trunkClient reports the switches connected over the port-channels and their trunks
+type trunkClient struct {
+	address  string
+	reports  map[string]domain.DeviceReport
+	trunks   map[string][]domain.TrunkPort
+	channels map[string][]domain.PortChannel
+}
+
+func (c *trunkClient) Connect(address string, _ string, _ string) error {
+	c.address = address
+	return nil
+}
+
+func (c *trunkClient) Close() error {
+	return nil
+}
+
+func (c *trunkClient) Info() (domain.DeviceReport, error) {
+	return c.reports[c.address], nil
+}
+
+func (c *trunkClient) Trunks() ([]domain.TrunkPort, error) {
+	return c.trunks[c.address], nil
+}
+
+func (c *trunkClient) VLANs() ([]domain.VLAN, error) {
+	return nil, nil
+}
+
+func (c *trunkClient) PortChannels() ([]domain.PortChannel, error) {
+	return c.channels[c.address], nil
+}
+
+func TestNetworkBuilder_BuildPortChannelTrunk(t *testing.T) {
+	allowed, _ := domain.ParseVLANSet("10,20")
+	client := &trunkClient{
+		reports: map[string]domain.DeviceReport{
+			"10.0.0.1": {Name: "core", Neighbors: []domain.NeighborReport{
+				{Name: "acc", Address: "10.0.0.2", Addresses: []string{"10.0.0.2"}, LocalPort: "GigabitEthernet1/0/2", RemotePort: "Gi0/1"},
+			}},
+			"10.0.0.2": {Name: "acc"},
+		},
+		//the trunk is listed as the port-channel, CDP reports its member port
+		trunks:   map[string][]domain.TrunkPort{"10.0.0.1": {{Port: "Po1", VLANs: domain.PortVLANs{Native: 1, Allowed: allowed}}}},
+		channels: map[string][]domain.PortChannel{"10.0.0.1": {{Port: "Po1", Members: []string{"Gi1/0/1", "Gi1/0/2"}}}},
+	}
+	builder := usecase.NewNetworkBuilder(client, usecase.WithVLANs(), usecase.WithPollInterval(time.Millisecond))
+	builder.Build(context.Background(), "10.0.0.1", "admin", "admin-pass")
+
+	core, _ := builder.Network().Switch("10.0.0.1")
+	acc, err := builder.Network().Switch("10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := builder.Network().LinksBetween(core, acc)
+	if len(links) != 1 {
+		t.Fatalf("LinksBetween() = %v, want one link", links)
+	}
+	if vlans := links[0].FromVLANs(); vlans.Native != 1 || vlans.Allowed.String() != "10,20" {
+		t.Errorf("FromVLANs() = %v, want the VLANs of the trunk Po1", vlans)
+	}
+}
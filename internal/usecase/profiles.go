@@ -0,0 +1,43 @@
+package usecase
+
+import "github.com/vps2/cisco-switches-crawler/internal/domain"
+
+// Connection - the client and the credentials connecting to the switch
+type Connection struct {
+	Client   Client //nil - the client selected by the address of the switch
+	User     string //empty - the user of the crawl
+	Password string //empty - the password of the crawl
+	Profile  string //the description of the effective connection parameters, shown in the verbose mode
+}
+
+// ProfileResolver - source of the connection parameters of the switches, e.g. the profiles of the config file
+// matched by the address or the names of the switch
+type ProfileResolver interface {
+	Resolve(address string, names ...string) (Connection, bool)
+}
+
+// resolveConnection returns the client and the credentials of the switch: of the profile matching the switch,
+// otherwise the client and the credentials given
+func resolveConnection(profiles ProfileResolver, sw domain.Switch, client Client, user string, password string) Connection {
+	conn := Connection{Client: client, User: user, Password: password}
+	if profiles == nil {
+		return conn
+	}
+
+	profile, ok := profiles.Resolve(sw.Address(), sw.Name(), sw.DNSName())
+	if !ok {
+		return conn
+	}
+	if profile.Client != nil {
+		conn.Client = profile.Client
+	}
+	if profile.User != "" {
+		conn.User = profile.User
+	}
+	if profile.Password != "" { //the user without the password keeps the password of the crawl
+		conn.Password = profile.Password
+	}
+	conn.Profile = profile.Profile
+
+	return conn
+}
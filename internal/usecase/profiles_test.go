@@ -0,0 +1,87 @@
+package usecase_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+)
+
+// recordingClient remembers the credentials of the connections
+type recordingClient struct {
+	name        string
+	connections []string
+}
+
+func (c *recordingClient) Connect(address string, user string, password string) error {
+	c.connections = append(c.connections, address+" "+user+":"+password)
+	return nil
+}
+
+func (c *recordingClient) Close() error {
+	return nil
+}
+
+func (c *recordingClient) Info() (domain.DeviceReport, error) {
+	return domain.DeviceReport{Name: c.name}, nil
+}
+
+// fakeProfiles resolves the profile of the switches by the name
+type fakeProfiles map[string]usecase.Connection
+
+func (p fakeProfiles) Resolve(address string, names ...string) (usecase.Connection, bool) {
+	for _, name := range names {
+		if conn, ok := p[name]; ok {
+			return conn, true
+		}
+	}
+	return usecase.Connection{}, false
+}
+
+type discardStore struct{}
+
+func (discardStore) Save(usecase.DeviceCommands) error {
+	return nil
+}
+
+func TestExecutor_RunProfiles(t *testing.T) {
+	terminalServer := &recordingClient{name: "ts"}
+	profiles := fakeProfiles{
+		"ts-sw1.corp.local": {Client: terminalServer, User: "console", Password: "console-pass", Profile: "profile ts"},
+		"SW2":               {Password: "sw2-pass"},
+		"SW3":               {User: "netops"},
+	}
+
+	tests := []struct {
+		name        string
+		dnsName     string
+		swName      string
+		wantDefault []string
+		wantTS      []string
+	}{
+		{"terminal server", "ts-sw1.corp.local", "", nil, []string{"10.0.0.1 console:console-pass"}},
+		{"password only", "", "SW2", []string{"10.0.0.1 admin:sw2-pass"}, nil},
+		{"user only", "", "SW3", []string{"10.0.0.1 netops:admin-pass"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminalServer.connections = nil
+			network := domain.NewNetwork()
+			sw, _ := domain.NewSwitch("10.0.0.1")
+			sw.SetDNSName(tt.dnsName)
+			sw.SetName(tt.swName)
+			network.AddSwitch(*sw)
+
+			client := &recordingClient{name: "default"}
+			executor := usecase.NewExecutor(client, []string{"show clock"}, discardStore{}, usecase.WithExecutorProfiles(profiles))
+			executor.Run(context.Background(), network, "admin", "admin-pass")
+
+			if !reflect.DeepEqual(client.connections, tt.wantDefault) || !reflect.DeepEqual(terminalServer.connections, tt.wantTS) {
+				t.Errorf("connections = %v and %v, want %v and %v", client.connections, terminalServer.connections, tt.wantDefault, tt.wantTS)
+			}
+		})
+	}
+}
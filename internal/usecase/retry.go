@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+)
+
+// RetryPolicy - repetition of the connections to the switches failed with the transient errors.
+// The delay before the retry doubles after every attempt up to MaxDelay, a random part of the delay
+// (up to a half) is dropped, so that the retries of the different switches do not coincide.
+type RetryPolicy struct {
+	Retries  int //the number of the retries after the first attempt, 0 disables them
+	Delay    time.Duration
+	MaxDelay time.Duration
+}
+
+var jitter = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// Backoff returns the delay before the retry after the failed attempt (starting from 1)
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.Delay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay -= time.Duration(jitter.Int63n(half + 1))
+	}
+
+	return delay
+}
+
+// IsRetryable reports whether the connection may succeed if it is repeated: the timeouts, the refused
+// and the reset connections, the switches having no free sessions. The failed authentication is fatal,
+// the retries could lock the account. The clients report the connection closed after the password
+// as the failed authentication, so io.EOF is retried only before the credentials are sent.
+func IsRetryable(err error) bool {
+	var timeout interface{ Timeout() bool }
+	var opErr *net.OpError
+	switch {
+	case err == nil, errors.Is(err, domain.ErrAuthentication):
+		return false
+	case errors.Is(err, domain.ErrDeviceBusy),
+		errors.As(err, &opErr) && opErr.Op == "dial", //e.g. the connection is refused
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, io.EOF): //the switch closed the connection before the login, e.g. all vty lines are busy
+		return true
+	case errors.As(err, &timeout):
+		return timeout.Timeout()
+	}
+
+	return false
+}
+
+// connect connects the client to the switch, repeating the attempts failed with the transient errors.
+// It returns the number of the attempts and the error of the last one.
+func connect(ctx context.Context, policy RetryPolicy, client Client, address string, user string, password string) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := client.Connect(address, user, password)
+		if err == nil || attempt > policy.Retries || !IsRetryable(err) {
+			return attempt, err
+		}
+
+		delay := policy.Backoff(attempt)
+		log.Printf("connect [%s]: attempt %d failed, retry in %v: %v", address, attempt, delay.Round(time.Millisecond), err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/vps2/cisco-switches-crawler/internal/domain"
+	"github.com/vps2/cisco-switches-crawler/internal/usecase"
+	"github.com/vps2/cisco-switches-crawler/pkg/expect"
+)
+
+func TestIsRetryable(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"refused", fmt.Errorf("client connect [10.0.0.1]: telnet connect: %w", refused), true},
+		{"busy", fmt.Errorf("client connect [10.0.0.1]: %w", domain.ErrDeviceBusy), true},
+		{"closed before login", fmt.Errorf("client connect [10.0.0.1]: expect: %w", io.EOF), true},
+		{"no output", fmt.Errorf("client connect [10.0.0.1]: expect: %w after 1m0s", expect.ErrTimeout), true},
+		{"deadline", fmt.Errorf("telnet read: %w", os.ErrDeadlineExceeded), true},
+		{"authentication", fmt.Errorf("client connect [10.0.0.1]: %w", domain.ErrAuthentication), false},
+		{"closed after password", fmt.Errorf("client connect [10.0.0.1]: the switch closed the connection after the password: %w",
+			domain.ErrAuthentication), false},
+		{"wrong address", fmt.Errorf("client connect [10.0.0.1]: %w", domain.ErrInvalidSwitchIPAddress), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := usecase.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := usecase.RetryPolicy{Retries: 5, Delay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{10, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.attempt), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := policy.Backoff(tt.attempt); got < tt.max/2 || got > tt.max {
+					t.Fatalf("RetryPolicy.Backoff() = %v, want between %v and %v", got, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+}
+
+// flakyClient fails the first connections with the errors
+type flakyClient struct {
+	recordingClient
+	errs []error
+}
+
+func (c *flakyClient) Connect(address string, user string, password string) error {
+	c.recordingClient.Connect(address, user, password)
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+// resultStore keeps the results of the executor
+type resultStore []usecase.DeviceCommands
+
+func (s *resultStore) Save(result usecase.DeviceCommands) error {
+	*s = append(*s, result)
+	return nil
+}
+
+func TestExecutor_RunRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		retries      int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{"no errors", nil, 3, 1, false},
+		{"busy twice", []error{domain.ErrDeviceBusy, io.EOF}, 3, 3, false},
+		{"retries exhausted", []error{domain.ErrDeviceBusy, domain.ErrDeviceBusy, domain.ErrDeviceBusy}, 2, 3, true},
+		{"authentication", []error{domain.ErrAuthentication, nil}, 3, 1, true},
+		{"retries disabled", []error{domain.ErrDeviceBusy}, 0, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := domain.NewNetwork()
+			sw, _ := domain.NewSwitch("10.0.0.1")
+			network.AddSwitch(*sw)
+
+			var store resultStore
+			client := &flakyClient{errs: tt.errs}
+			policy := usecase.RetryPolicy{Retries: tt.retries, Delay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+			executor := usecase.NewExecutor(client, []string{"show clock"}, &store, usecase.WithExecutorRetry(policy))
+			executor.Run(context.Background(), network, "admin", "admin-pass")
+
+			if len(store) != 1 {
+				t.Fatalf("results = %v, want one result", store)
+			}
+			if got := store[0]; got.Attempts != tt.wantAttempts || (got.Error != "") != tt.wantErr {
+				t.Errorf("result = %d attempts, error %q, want %d attempts, error %v", got.Attempts, got.Error, tt.wantAttempts, tt.wantErr)
+			}
+			if len(client.connections) != tt.wantAttempts {
+				t.Errorf("connections = %v, want %d", client.connections, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestNetworkBuilder_BuildRetry(t *testing.T) {
+	client := &flakyClient{errs: []error{domain.ErrDeviceBusy, io.EOF}}
+	policy := usecase.RetryPolicy{Retries: 3, Delay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	builder := usecase.NewNetworkBuilder(client, usecase.WithRetry(policy), usecase.WithPollInterval(time.Millisecond))
+	builder.Build(context.Background(), "10.0.0.1", "admin", "admin-pass")
+
+	sw, err := builder.Network().Switch("10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sw.Status() != domain.StatusCrawled || sw.Attempts() != 3 {
+		t.Errorf("switch = %v after %d attempts, want %v after 3", sw.Status(), sw.Attempts(), domain.StatusCrawled)
+	}
+	if len(client.connections) != 3 {
+		t.Errorf("connections = %v, want 3", client.connections)
+	}
+}
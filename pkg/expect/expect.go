@@ -0,0 +1,181 @@
+// Package expect waits for the patterns in the output of an interactive session, e.g. telnet, and reacts to them
+package expect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+)
+
+const readSize = 4096
+
+// ErrTimeout - no pattern was found in time. Like the timeouts of net, it reports Timeout() == true.
+var ErrTimeout error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout expired" }
+func (timeoutError) Timeout() bool { return true }
+
+// Action - what the session does after the handler of the matched pattern
+type Action int
+
+const (
+	Stop     Action = iota //return the match from Expect
+	Continue               //wait for the patterns further
+)
+
+// Case - the pattern awaited in the output and its handler. Without the handler the session stops on the match.
+type Case struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Handle  func(m Match) (Action, error)
+}
+
+// Match - the pattern found in the output
+type Match struct {
+	Case   string   //the name of the matched case
+	Before string   //the output between the previous match and this one
+	Groups []string //the text of the match and its submatches
+	Time   time.Time
+}
+
+func (m Match) Text() string {
+	return m.Groups[0]
+}
+
+// deadliner - connection able to interrupt the blocked reading, e.g. net.Conn
+type deadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+type Option func(*Session)
+
+// WithTimeout limits the time of waiting for new data. The connection must implement SetReadDeadline,
+// otherwise the session waits until the connection is closed.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Session) {
+		s.timeout = timeout
+	}
+}
+
+// WithEcho copies the received data to the writer, e.g. to show the session
+func WithEcho(w io.Writer) Option {
+	return func(s *Session) {
+		s.echo = w
+	}
+}
+
+// Session reads the connection in chunks and searches the patterns only in the new lines of the output,
+// so the cost of the search does not grow with the length of the output. A pattern must not span more than two
+// lines: the search starts at the end of the line preceding the new data.
+type Session struct {
+	conn       io.ReadWriter
+	timeout    time.Duration
+	echo       io.Writer
+	buffer     []byte //the received data not consumed by the matches
+	scanned    int    //the length of the buffer searched for the patterns
+	transcript []Match
+}
+
+func NewSession(conn io.ReadWriter, opts ...Option) *Session {
+	s := &Session{
+		conn: conn,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Send writes the text to the connection
+func (s *Session) Send(text string) error {
+	if _, err := s.conn.Write([]byte(text)); err != nil {
+		return fmt.Errorf("expect send: %w", err)
+	}
+
+	return nil
+}
+
+// Expect reads the connection until one of the patterns is found and calls its handler. The earliest match wins,
+// the cases are checked in the order of the arguments if several patterns match at the same position.
+// The output before the match and the match itself are consumed.
+func (s *Session) Expect(cases ...Case) (Match, error) {
+	chunk := make([]byte, readSize)
+	for {
+		if match, c, ok := s.find(cases); ok {
+			s.transcript = append(s.transcript, Match{Case: match.Case, Groups: match.Groups, Time: match.Time})
+			if c.Handle == nil {
+				return match, nil
+			}
+			action, err := c.Handle(match)
+			if err != nil || action == Stop {
+				return match, err
+			}
+			continue
+		}
+
+		if d, ok := s.conn.(deadliner); ok && s.timeout > 0 {
+			d.SetReadDeadline(time.Now().Add(s.timeout))
+		}
+		n, err := s.conn.Read(chunk)
+		if n > 0 {
+			if s.echo != nil {
+				s.echo.Write(chunk[:n])
+			}
+			s.buffer = append(s.buffer, chunk[:n]...)
+			continue
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return Match{}, fmt.Errorf("expect: %w after %v", ErrTimeout, s.timeout)
+		} else if err != nil {
+			return Match{}, fmt.Errorf("expect: %w", err)
+		}
+	}
+}
+
+// find searches the patterns in the data received since the previous search
+func (s *Session) find(cases []Case) (Match, Case, bool) {
+	start := bytes.LastIndexByte(s.buffer[:s.scanned], '\n')
+	if start < 0 {
+		start = 0
+	}
+	s.scanned = len(s.buffer)
+
+	best, bestLoc := -1, []int(nil)
+	for i, c := range cases {
+		loc := c.Pattern.FindSubmatchIndex(s.buffer[start:])
+		if loc != nil && (bestLoc == nil || loc[0] < bestLoc[0]) {
+			best, bestLoc = i, loc
+		}
+	}
+	if best < 0 {
+		return Match{}, Case{}, false
+	}
+
+	match := Match{Case: cases[best].Name, Before: string(s.buffer[:start+bestLoc[0]]), Time: time.Now()}
+	for i := 0; i < len(bestLoc); i += 2 {
+		if bestLoc[i] < 0 {
+			match.Groups = append(match.Groups, "")
+			continue
+		}
+		match.Groups = append(match.Groups, string(s.buffer[start+bestLoc[i]:start+bestLoc[i+1]]))
+	}
+
+	s.buffer = s.buffer[start+bestLoc[1]:]
+	s.scanned = 0
+
+	return match, cases[best], true
+}
+
+// Transcript returns the matches of the session in the order they were found. The output before the matches
+// is not kept.
+func (s *Session) Transcript() []Match {
+	return s.transcript
+}
@@ -0,0 +1,175 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeConn returns the output in chunks of the given size and records the written data
+type fakeConn struct {
+	output   string
+	chunk    int
+	written  []string
+	deadline time.Time
+}
+
+func (f *fakeConn) Read(p []byte) (int, error) {
+	if f.output == "" {
+		if !f.deadline.IsZero() {
+			return 0, os.ErrDeadlineExceeded
+		}
+		return 0, io.EOF
+	}
+	n := f.chunk
+	if n > len(p) {
+		n = len(p)
+	}
+	n = copy(p[:n], f.output)
+	f.output = f.output[n:]
+	return n, nil
+}
+
+func (f *fakeConn) Write(p []byte) (int, error) {
+	f.written = append(f.written, string(p))
+	return len(p), nil
+}
+
+func (f *fakeConn) SetReadDeadline(t time.Time) error {
+	f.deadline = t
+	return nil
+}
+
+var (
+	promptRe = regexp.MustCompile(`\n(SW1#)$`)
+	moreRe   = regexp.MustCompile(` --More-- `)
+	userRe   = regexp.MustCompile(`Username: `)
+)
+
+func TestSession_Expect(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		chunk      int
+		wantCase   string
+		wantBefore string
+		wantErr    error
+	}{
+		{"one chunk", "show clock\r\n10:15:01\r\nSW1#", readSize, "prompt", "show clock\r\n10:15:01\r", nil},
+		{"byte by byte", "show clock\r\n10:15:01\r\nSW1#", 1, "prompt", "show clock\r\n10:15:01\r", nil},
+		{"earliest match", "Username: \r\nSW1#", readSize, "user", "", nil},
+		{"prompt not at the end", "show run\r\nSW1#\r\nend", readSize, "", "", io.EOF},
+		{"timeout", "show clock\r\n", readSize, "", "", ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &fakeConn{output: tt.output, chunk: tt.chunk}
+			var opts []Option
+			if tt.wantErr == ErrTimeout {
+				opts = append(opts, WithTimeout(time.Second))
+			}
+			session := NewSession(conn, opts...)
+
+			got, err := session.Expect(
+				Case{Name: "prompt", Pattern: promptRe},
+				Case{Name: "user", Pattern: userRe},
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Session.Expect() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.Case != tt.wantCase || got.Before != tt.wantBefore {
+				t.Errorf("Session.Expect() = %q before %q, want %q before %q", got.Case, got.Before, tt.wantCase, tt.wantBefore)
+			}
+		})
+	}
+}
+
+func TestSession_ExpectHandlers(t *testing.T) {
+	conn := &fakeConn{output: "show run\r\nhostname SW1\r\n --More-- interface Gi0/1\r\n --More-- end\r\nSW1#", chunk: 7}
+	session := NewSession(conn)
+
+	var output strings.Builder
+	more := Case{Name: "more", Pattern: moreRe, Handle: func(m Match) (Action, error) {
+		output.WriteString(m.Before)
+		return Continue, session.Send(" ")
+	}}
+	match, err := session.Expect(Case{Name: "prompt", Pattern: promptRe}, more)
+	if err != nil {
+		t.Fatalf("Session.Expect() error = %v", err)
+	}
+	output.WriteString(match.Before)
+
+	if want := "show run\r\nhostname SW1\r\ninterface Gi0/1\r\nend\r"; output.String() != want {
+		t.Errorf("output = %q, want %q", output.String(), want)
+	}
+	if want := []string{" ", " "}; !reflect.DeepEqual(conn.written, want) {
+		t.Errorf("written = %q, want %q", conn.written, want)
+	}
+
+	var cases []string
+	for _, m := range session.Transcript() {
+		cases = append(cases, m.Case)
+	}
+	if want := []string{"more", "more", "prompt"}; !reflect.DeepEqual(cases, want) {
+		t.Errorf("Session.Transcript() = %v, want %v", cases, want)
+	}
+	if match.Groups[1] != "SW1#" {
+		t.Errorf("Match.Groups = %q, want the prompt in the first group", match.Groups)
+	}
+}
+
+func TestSession_ExpectHandlerError(t *testing.T) {
+	errDenied := errors.New("access denied")
+	session := NewSession(&fakeConn{output: "% Access denied\r\nUsername: ", chunk: readSize})
+
+	_, err := session.Expect(
+		Case{Name: "user", Pattern: userRe},
+		Case{Name: "denied", Pattern: regexp.MustCompile(`Access denied`), Handle: func(Match) (Action, error) {
+			return Stop, errDenied
+		}},
+	)
+	if !errors.Is(err, errDenied) {
+		t.Errorf("Session.Expect() error = %v, want %v", err, errDenied)
+	}
+}
+
+// cdpOutput returns the output of "show cdp neighbors detail" with the number of lines
+func cdpOutput(lines int) string {
+	var sb strings.Builder
+	sb.WriteString("sh cdp nei det\r\n")
+	for i := 0; lines > 0; i++ {
+		fmt.Fprintf(&sb, "-------------------------\r\n"+
+			"Device ID: SW%d\r\n"+
+			"Entry address(es): \r\n"+
+			"  IP address: 10.0.%d.%d\r\n"+
+			"Platform: cisco WS-C2960-24TT-L,  Capabilities: Switch IGMP \r\n"+
+			"Interface: GigabitEthernet0/%d,  Port ID (outgoing port): GigabitEthernet0/1\r\n"+
+			"Holdtime : 155 sec\r\n"+
+			"\r\n"+
+			"Version :\r\n"+
+			"Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 12.2(55)SE5, RELEASE SOFTWARE (fc1)\r\n", i, i/250, i%250, i%48)
+		lines -= 10
+	}
+	sb.WriteString("SW1#")
+
+	return sb.String()
+}
+
+func BenchmarkSession_Expect(b *testing.B) {
+	output := cdpOutput(5000)
+	b.SetBytes(int64(len(output)))
+
+	for i := 0; i < b.N; i++ {
+		session := NewSession(&fakeConn{output: output, chunk: readSize})
+		if _, err := session.Expect(Case{Name: "prompt", Pattern: promptRe}, Case{Name: "more", Pattern: moreRe}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
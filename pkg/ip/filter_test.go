@@ -17,6 +17,10 @@ func TestFilter_Add(t *testing.T) {
 		{input: "192.168.1.", wantErr: true},
 		{input: "", wantErr: true},
 		{input: "192.168.1.0/33", wantErr: true},
+		{input: "2001:db8::1", wantErr: false},
+		{input: "2001:db8:10::/48", wantErr: false},
+		{input: "2001:db8::/129", wantErr: true},
+		{input: "2001:db8:::1", wantErr: true},
 	}
 
 	filter := NewFilter()
@@ -112,3 +116,34 @@ func TestFilter_Allow(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_AllowIPv6(t *testing.T) {
+	filter := NewFilter()
+	for _, address := range []string{"2001:DB8::1", "2001:db8:10::/48", "2001:db8:20::5/128", "10.0.0.0/8"} {
+		if err := filter.Add(address); err != nil {
+			t.Fatalf("Filter.Add(%v) error = %v", address, err)
+		}
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{input: "2001:db8::1", want: true}, //the address is normalized
+		{input: "2001:db8:0:0::1", want: true},
+		{input: "2001:db8::2", want: false},
+		{input: "2001:db8:10:ffff::1", want: true},
+		{input: "2001:db8:11::1", want: false},
+		{input: "2001:db8:20::5", want: true},
+		{input: "::ffff:10.1.1.1", want: true}, //IPv4-mapped address
+		{input: "::a01:101", want: false},      //IPv6 address with the same bits as 10.1.1.1
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := filter.Allow(net.ParseIP(tt.input)); got != tt.want {
+				t.Errorf("Filter.Allow(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -23,3 +23,13 @@ func (q *Queue[T]) Pop() T {
 func (q *Queue[T]) IsEmpty() bool {
 	return q.list.Len() == 0
 }
+
+// Values returns the values of the queue from the front to the back without removing them
+func (q *Queue[T]) Values() []T {
+	values := make([]T, 0, q.list.Len())
+	for e := q.list.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value.(T))
+	}
+
+	return values
+}
@@ -2,6 +2,10 @@ package telnet
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/reiver/go-telnet"
@@ -9,6 +13,15 @@ import (
 
 const defaultWriteTimeout = 200 * time.Millisecond
 
+const readBufferSize = 4096
+
+const acceptTimeout = 5 * time.Second
+
+// Dialer opens the connections to the switches, e.g. through a jump host or a proxy
+type Dialer interface {
+	Dial(network string, address string) (net.Conn, error)
+}
+
 type Option func(*Client)
 
 // WriteTimeout this is a timeout in msec between write commands
@@ -18,10 +31,28 @@ func WriteTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithDialer connects to the switches with the dialer instead of the direct connection
+func WithDialer(dialer Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
 // Client wrapper over the telnet package "github.com/reiver/go-telnet"
 type Client struct {
 	conn         *telnet.Conn
 	writeTimeout time.Duration
+	dialer       Dialer
+	session      *session
+	deadline     time.Time
+}
+
+// session - the data received over one connection. Every connection has its own, so the reading of the closed
+// connection does not affect the next one.
+type session struct {
+	data chan byte     //the received data. Closed when the reading of the connection fails.
+	err  error         //the error of the reading, set before the data is closed
+	done chan struct{} //closed when the client is closed
 }
 
 func New(opts ...Option) *Client {
@@ -41,20 +72,98 @@ func (c *Client) Connect(address string, port int) error {
 		return fmt.Errorf("telnet client already connected to: %s", c.conn.RemoteAddr().String())
 	}
 
-	conn, err := telnet.DialTo(fmt.Sprintf("%s:%d", address, port))
+	var conn *telnet.Conn
+	var err error
+	if c.dialer != nil {
+		conn, err = c.dialThrough(net.JoinHostPort(address, strconv.Itoa(port)))
+	} else {
+		conn, err = telnet.DialTo(net.JoinHostPort(address, strconv.Itoa(port))) //IPv6 literals are enclosed in brackets
+	}
 	if err != nil {
 		return fmt.Errorf("telnet connect: %w", err)
 	}
 
 	c.conn = conn
+	c.session = &session{data: make(chan byte, readBufferSize), done: make(chan struct{})}
+	c.deadline = time.Time{}
+	go receive(conn, c.session)
 
 	return nil
 }
 
+// dialThrough connects to the address with the dialer. go-telnet dials the switches only by itself, so the connection
+// of the dialer is forwarded from a port of the loopback interface.
+func (c *Client) dialThrough(address string) (*telnet.Conn, error) {
+	remote, err := c.dialer.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		remote.Close()
+		return nil, err
+	}
+	defer listener.Close()
+
+	conn, err := telnet.DialTo(listener.Addr().String())
+	if err != nil {
+		remote.Close()
+		return nil, err
+	}
+
+	//the connections of other processes to the port are rejected
+	listener.(*net.TCPListener).SetDeadline(time.Now().Add(acceptTimeout))
+	for {
+		local, err := listener.Accept()
+		if err != nil {
+			conn.Close()
+			remote.Close()
+			return nil, err
+		}
+		if local.RemoteAddr().String() == conn.LocalAddr().String() {
+			go forward(local, remote)
+			return conn, nil
+		}
+		local.Close()
+	}
+}
+
+// forward copies the data between the connections until one of them is closed
+func forward(local net.Conn, remote net.Conn) {
+	go func() {
+		io.Copy(remote, local)
+		remote.Close()
+	}()
+
+	io.Copy(local, remote)
+	local.Close()
+}
+
+// receive reads the connection byte by byte, because go-telnet blocks until the whole buffer is filled.
+// So Read returns the data received so far instead of waiting for more.
+func receive(conn *telnet.Conn, s *session) {
+	var buffer [1]byte
+	for {
+		if _, err := conn.Read(buffer[:]); err != nil {
+			s.err = err
+			close(s.data)
+			return
+		}
+
+		select {
+		case s.data <- buffer[0]:
+		case <-s.done:
+			return
+		}
+	}
+}
+
 func (c *Client) Close() error {
 	if c.conn != nil {
 		err := c.conn.Close()
 		if err == nil {
+			close(c.session.done)
 			c.conn = nil
 		} else {
 			return fmt.Errorf("telnet close: %w", err)
@@ -64,8 +173,54 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// Read waits for the data and returns all the data received so far.
+// If the deadline is set and expired, the error wraps os.ErrDeadlineExceeded.
 func (c *Client) Read(p []byte) (n int, err error) {
-	return c.conn.Read(p)
+	if c.conn == nil {
+		return 0, fmt.Errorf("telnet read: not connected")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var timeout <-chan time.Time
+	if !c.deadline.IsZero() {
+		timer := time.NewTimer(time.Until(c.deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case b, ok := <-c.session.data:
+		if !ok {
+			return 0, c.session.err
+		}
+		p[n] = b
+		n++
+	case <-timeout:
+		return 0, fmt.Errorf("telnet read: %w", os.ErrDeadlineExceeded)
+	}
+
+	for n < len(p) {
+		select {
+		case b, ok := <-c.session.data:
+			if !ok {
+				return n, nil
+			}
+			p[n] = b
+			n++
+		default:
+			return n, nil
+		}
+	}
+
+	return n, nil
+}
+
+// SetReadDeadline sets the time after which Read fails. The zero time disables the deadline.
+func (c *Client) SetReadDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
 }
 
 func (c *Client) Write(p []byte) (n int, err error) {
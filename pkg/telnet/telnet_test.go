@@ -0,0 +1,108 @@
+package telnet
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+// fakeDialer connects to the switch over the pipe, as a jump host would, and records the dialed addresses
+type fakeDialer struct {
+	addresses []string
+	sw        net.Conn //the side of the switch
+	err       error
+}
+
+func (d *fakeDialer) Dial(network string, address string) (net.Conn, error) {
+	d.addresses = append(d.addresses, network+" "+address)
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	client, sw := net.Pipe()
+	d.sw = sw
+	return client, nil
+}
+
+func TestClient_ConnectDialer(t *testing.T) {
+	dialer := &fakeDialer{}
+	client := New(WithDialer(dialer), WriteTimeout(0))
+	if err := client.Connect("2001:db8::1", 23); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if want := "tcp [2001:db8::1]:23"; len(dialer.addresses) != 1 || dialer.addresses[0] != want {
+		t.Errorf("dialed %v, want %v", dialer.addresses, want)
+	}
+
+	go dialer.sw.Write([]byte("Username: "))
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	var got []byte
+	buffer := make([]byte, 64)
+	for len(got) < len("Username: ") {
+		n, err := client.Read(buffer)
+		if err != nil {
+			t.Fatalf("Read() error = %v, got %q", err, got)
+		}
+		got = append(got, buffer[:n]...)
+	}
+	if string(got) != "Username: " {
+		t.Errorf("Read() = %q, want the output of the switch", got)
+	}
+
+	go client.Write([]byte("admin\r\n"))
+	written := make([]byte, len("admin\r\n"))
+	dialer.sw.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(dialer.sw, written); err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != "admin\r\n" {
+		t.Errorf("the switch received %q, want the written data", written)
+	}
+}
+
+func TestClient_ConnectDialerError(t *testing.T) {
+	client := New(WithDialer(&fakeDialer{err: errUnreachable}))
+	if err := client.Connect("10.0.0.1", 23); !errors.Is(err, errUnreachable) {
+		t.Errorf("Connect() error = %v, want %v", err, errUnreachable)
+	}
+}
+
+func TestClient_ReconnectAfterClosed(t *testing.T) {
+	dialer := &fakeDialer{}
+	client := New(WithDialer(dialer), WriteTimeout(0))
+	if err := client.Connect("10.0.0.1", 23); err != nil {
+		t.Fatal(err)
+	}
+
+	//the switch closes the first connection, its error must not leak into the next one
+	dialer.sw.Close()
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	buffer := make([]byte, 64)
+	if _, err := client.Read(buffer); err == nil || errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Read() error = %v, want the closed connection", err)
+	}
+	client.Close()
+
+	if err := client.Connect("10.0.0.1", 23); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	go dialer.sw.Write([]byte("Username: "))
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	var got []byte
+	for len(got) < len("Username: ") {
+		n, err := client.Read(buffer)
+		if err != nil {
+			t.Fatalf("Read() after Connect() error = %v, got %q", err, got)
+		}
+		got = append(got, buffer[:n]...)
+	}
+}